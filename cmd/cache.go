@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Ladicle/tcardgen/pkg/canvas"
+	"github.com/Ladicle/tcardgen/pkg/canvas/fontfamily"
+	"github.com/Ladicle/tcardgen/pkg/config"
+	"github.com/Ladicle/tcardgen/pkg/hugo"
+	"github.com/Ladicle/tcardgen/pkg/manifest"
+	"github.com/Ladicle/tcardgen/pkg/tcardgen"
+)
+
+// cardCache skips generating the card whose inputs are not changed since the last generation.
+type cardCache struct {
+	manifest *manifest.Manifest
+	force    bool
+}
+
+func newCardCache(filename string, force bool) (*cardCache, error) {
+	m, err := manifest.Load(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cache manifest %q: %w", filename, err)
+	}
+	return &cardCache{manifest: m, force: force}, nil
+}
+
+// baseDigest returns the digest of the inputs which are shared by all cards.
+func baseDigest(resourcesDigest string, format canvas.Format, quality int) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s:%d\n", resourcesDigest, format, quality)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// resourcesDigest returns the digest of the drawing configuration, site configuration, template image, and fonts.
+func resourcesDigest(cnf *config.DrawingConfig, site *hugo.SiteConfig, fontDir string) (string, error) {
+	h := sha256.New()
+	if err := json.NewEncoder(h).Encode(cnf); err != nil {
+		return "", err
+	}
+	if site != nil {
+		if err := json.NewEncoder(h).Encode(site); err != nil {
+			return "", err
+		}
+	}
+	if err := hashFile(h, cnf.Template); err != nil {
+		return "", err
+	}
+	fonts, err := filepath.Glob(filepath.Join(fontDir, "*"+fontfamily.TrueTypeFontExt))
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(fonts)
+	for _, f := range fonts {
+		if err := hashFile(h, f); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cardDigest returns the digest of the inputs of the card, which are the shared inputs, the front
+// matter fields which are drawn, and images which are used by the card. The other fields such as
+// the draft flag do not change the card.
+func cardDigest(base string, fm *hugo.FrontMatter, renderer *tcardgen.Renderer, opts ...tcardgen.RenderOption) (string, error) {
+	data, err := renderer.CardData(fm)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	io.WriteString(h, base)
+	if err := json.NewEncoder(h).Encode(data); err != nil {
+		return "", err
+	}
+	for _, path := range renderer.ImageFiles(fm, opts...) {
+		if err := hashFile(h, path); errors.Is(err, os.ErrNotExist) {
+			// the missing image is skipped, and the card is changed when it is added
+			fmt.Fprintf(h, "%s\n", path)
+		} else if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(h hash.Hash, filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintf(h, "%s\n", filename)
+	_, err = io.Copy(h, f)
+	return err
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ladicle/tcardgen/pkg/hugo"
+	"github.com/Ladicle/tcardgen/pkg/tcardgen"
+)
+
+func TestCardDigest(t *testing.T) {
+	r := newTestResources(t)
+	newFM := func() *hugo.FrontMatter {
+		return &hugo.FrontMatter{
+			Title:    "Title",
+			Author:   "Ladicle",
+			Category: "Blog",
+			Tags:     []string{"go"},
+			Date:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Params:   map[string]interface{}{"title": "Title", "draft": true},
+		}
+	}
+	digest := func(fm *hugo.FrontMatter) string {
+		d, err := cardDigest("base", fm, r.renderer, tcardgen.WithContentPath("content/posts/foo.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	want := digest(newFM())
+
+	fm := newFM()
+	fm.Params["draft"] = false
+	fm.WordCount = 100
+	if got := digest(fm); got != want {
+		t.Error("the fields which are not drawn must not change the digest")
+	}
+
+	fm = newFM()
+	fm.Title = "Other"
+	if got := digest(fm); got == want {
+		t.Error("the drawn field must change the digest")
+	}
+}
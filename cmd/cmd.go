@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
-	"image"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,11 +19,17 @@ import (
 	"github.com/Ladicle/tcardgen/pkg/canvas/fontfamily"
 	"github.com/Ladicle/tcardgen/pkg/config"
 	"github.com/Ladicle/tcardgen/pkg/hugo"
+	"github.com/Ladicle/tcardgen/pkg/locale"
+	"github.com/Ladicle/tcardgen/pkg/manifest"
+	"github.com/Ladicle/tcardgen/pkg/tcardgen"
+	"github.com/Ladicle/tcardgen/pkg/tmpl"
 )
 
 const (
 	defaultFontDir = "font"
 	defaultOutput  = "out/"
+	// defaultBundleName is the name which Hugo themes use for the featured image of the page.
+	defaultBundleName = "featured"
 
 	longDesc = `Generate TwitterCard(OGP) images for your Hugo posts.
 Supported front-matters are title, author, categories, tags, and date.`
@@ -30,9 +39,18 @@ tcardgen --fontDir=font --output=example --template=example/template.png example
 # Generate a image and output to the example directory as "featured.png".
 tcardgen --fontDir=font --output=example/featured.png --template=example/template.png example/blog-post.md
 
+# Generate a JPEG image.
+tcardgen --fontDir=font --output=example/featured.jpg --quality=80 example/blog-post.md
+
 # Generate multiple images.
 tcardgen --template=example/template.png example/*.md
 
+# Generate images of the content directory into the same tree.
+tcardgen --output=static/tcard content/
+
+# Generate images of the content directory of the Hugo site in the current directory.
+tcardgen --output=static/tcard
+
 # Genrate an image based on the drawing configuration.
 tcardgen --config=config.yaml example/*.md`
 )
@@ -50,18 +68,38 @@ type IOStreams struct {
 }
 
 type RootCommandOption struct {
-	files   []string
-	fontDir string
-	outDir  string
-	output  string
-	tplImg  string
-	config  string
+	paths        []string
+	files        []contentFile
+	includeIndex bool
+	include      []string
+	exclude      []string
+	filter       *contentFilter
+	fontDir      string
+	outDir       string
+	bundle       bool
+	bundleName   string
+	output       string
+	format       string
+	quality      int
+	jobs         int
+	cache        string
+	force        bool
+	tplImg       string
+	config       string
+	outFormat    canvas.Format
+
+	writeFrontMatter bool
+	frontMatterKey   string
+	staticDir        string
+
+	// siteDir is the Hugo site directory. Empty means the current directory, where the site configuration is optional.
+	siteDir string
 }
 
 func NewRootCmd() *cobra.Command {
 	opt := RootCommandOption{}
 	cmd := &cobra.Command{
-		Use:                   "tcardgen [-f <FONTDIR>] [-o <OUTPUT>] [-t <TEMPLATE>] [-c <CONFIG>] <FILE>...",
+		Use:                   "tcardgen [-f <FONTDIR>] [-o <OUTPUT>] [-t <TEMPLATE>] [-c <CONFIG>] [<FILE|DIR>...]",
 		Version:               version,
 		DisableFlagsInUseLine: true,
 		SilenceUsage:          true,
@@ -69,6 +107,7 @@ func NewRootCmd() *cobra.Command {
 		Short:                 "Generate TwitterCard(OGP) image for your Hugo posts.",
 		Long:                  longDesc,
 		Example:               example,
+		Args:                  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			streams := IOStreams{
 				Out:    os.Stdout,
@@ -80,160 +119,426 @@ func NewRootCmd() *cobra.Command {
 			return opt.Run(streams, time.Now())
 		},
 	}
-	cmd.Flags().StringVarP(&opt.fontDir, "fontDir", "f", defaultFontDir, "Set a font directory.")
-	cmd.Flags().StringVarP(&opt.outDir, "outDir", "", "", "(DEPRECATED) Set an output directory.")
-	cmd.Flags().StringVarP(&opt.output, "output", "o", defaultOutput, "Set an output directory or filename (only png format).")
-	cmd.Flags().StringVarP(&opt.tplImg, "template", "t", "", fmt.Sprintf("Set a template image file. (default %s)", config.DefaultTemplate))
-	cmd.Flags().StringVarP(&opt.config, "config", "c", "", "Set a drawing configuration file.")
+	cmd.PersistentFlags().StringVarP(&opt.fontDir, "fontDir", "f", defaultFontDir, "Set a font directory.")
+	cmd.PersistentFlags().StringVarP(&opt.outDir, "outDir", "", "", "(DEPRECATED) Set an output directory.")
+	cmd.PersistentFlags().StringVarP(&opt.output, "output", "o", defaultOutput, "Set an output directory, filename (png, jpg, webp, or svg format), or filename template.")
+	cmd.PersistentFlags().StringVarP(&opt.format, "format", "", "", "Set an output format (png, jpeg, webp, or svg). (default is the output file extension or png)")
+	cmd.PersistentFlags().IntVarP(&opt.quality, "quality", "", canvas.DefaultJPEGQuality, "Set a JPEG quality from 1 to 100.")
+	cmd.PersistentFlags().IntVarP(&opt.jobs, "jobs", "j", runtime.GOMAXPROCS(0), "Set the number of cards which are generated concurrently.")
+	cmd.PersistentFlags().StringVarP(&opt.cache, "cache", "", manifest.DefaultFilename, "Set a cache manifest file to skip generating unchanged cards. Empty disables the cache.")
+	cmd.PersistentFlags().BoolVarP(&opt.force, "force", "", false, "Generate all cards even if they are not changed.")
+	cmd.PersistentFlags().StringVarP(&opt.tplImg, "template", "t", "", fmt.Sprintf("Set a template image file. (default %s)", config.DefaultTemplate))
+	cmd.PersistentFlags().StringVarP(&opt.config, "config", "c", "", "Set a drawing configuration file.")
+	cmd.PersistentFlags().StringSliceVarP(&opt.include, "include", "", nil, "Set glob patterns of the content files to include from the directories.")
+	cmd.PersistentFlags().StringSliceVarP(&opt.exclude, "exclude", "", nil, "Set glob patterns of the content files to exclude from the directories.")
+	cmd.PersistentFlags().BoolVarP(&opt.bundle, "bundle", "", false, "Write the cards of page bundles (index.md and _index.md) into the bundle directories.")
+	cmd.PersistentFlags().StringVarP(&opt.bundleName, "bundle-name", "", defaultBundleName, "Set a card name in the bundle directories without the extension.")
+	cmd.PersistentFlags().BoolVarP(&opt.includeIndex, "include-index", "", false, "Include the section pages (_index.md) in the directories.")
+	cmd.PersistentFlags().BoolVarP(&opt.writeFrontMatter, "write-frontmatter", "", false, "Write the card path into the front matter of the contents.")
+	cmd.PersistentFlags().StringVarP(&opt.frontMatterKey, "frontmatter-key", "", defaultFrontMatterKey, "Set a front matter key to write the card path. A dotted key (e.g. params.og) refers to the nested value.")
+	cmd.PersistentFlags().StringVarP(&opt.staticDir, "static-dir", "", defaultStaticDir, "Set a Hugo static directory to convert the card path to the URL path.")
+	cmd.PersistentFlags().StringVarP(&opt.siteDir, "site", "", "", "Set a Hugo site directory to read the defaults from the site configuration. (default is the current directory if it has the configuration)")
+	cmd.CompletionOptions.DisableDefaultCmd = true
+	cmd.AddCommand(NewWatchCmd(&opt), NewServeCmd(&opt), NewServerCmd(&opt))
 	return cmd
 }
 
 func (o *RootCommandOption) Validate(cmd *cobra.Command, args []string) error {
+	// the warning of the site configuration is written when the resources are loaded
+	site, err := o.loadSite(io.Discard)
+	if err != nil {
+		return err
+	}
 	if len(args) < 1 {
-		return errors.New("required argument <FILE> is not set")
+		if site == nil {
+			return errors.New("required argument <FILE> is not set, and the site configuration is not found")
+		}
+		// the content directory of the site is the default input
+		args = []string{filepath.Join(o.siteDir, site.ContentDir)}
+	}
+	filter, err := newContentFilter(o.includeIndex, o.include, o.exclude)
+	if err != nil {
+		return err
+	}
+	files, err := filter.contentFiles(args)
+	if err != nil {
+		return err
 	}
 
-	isSpecifiedOutputFilename := strings.HasSuffix(o.output, ".png")
-	if isSpecifiedOutputFilename && len(args) > 1 {
+	if o.isOutputTemplate() {
+		if _, err := tmpl.Parse(o.output); err != nil {
+			return err
+		}
+	}
+	extFormat, hasExt := canvas.FormatFromExt(o.output)
+	isSpecifiedOutputFilename := hasExt && !o.isOutputTemplate()
+	if isSpecifiedOutputFilename && len(files) > 1 {
 		return errors.New("cannot accept multiple <FILE>s when you specify output filename")
-	} else if !isSpecifiedOutputFilename && o.output != defaultOutput {
+	} else if !hasExt && !o.isOutputTemplate() && o.output != defaultOutput {
 		// "/" suffix is needed to correctly split directory and filename by filepath.Split()
 		o.output += "/"
 	}
 
-	o.files = args
+	switch {
+	case o.format != "":
+		f, err := canvas.ParseFormat(o.format)
+		if err != nil {
+			return err
+		}
+		if hasExt && f != extFormat {
+			return fmt.Errorf("output filename %q does not match %s format", o.output, f)
+		}
+		o.outFormat = f
+	case hasExt:
+		o.outFormat = extFormat
+	default:
+		o.outFormat = canvas.PNG
+	}
+	if err := o.validateLimits(); err != nil {
+		return err
+	}
+	if o.bundle {
+		if isSpecifiedOutputFilename {
+			return errors.New("cannot use bundle mode when you specify output filename")
+		}
+		if o.bundleName == "" || strings.ContainsAny(o.bundleName, `/\`) || filepath.Ext(o.bundleName) != "" {
+			return fmt.Errorf("bundle name %q must be a file name without the extension", o.bundleName)
+		}
+	}
+	if o.writeFrontMatter {
+		if err := validateFrontMatterKey(o.frontMatterKey); err != nil {
+			return err
+		}
+		if err := o.validateFrontMatterOutput(files); err != nil {
+			return err
+		}
+	}
+
+	o.paths, o.files, o.filter = args, files, filter
+	return nil
+}
+
+func (o *RootCommandOption) validateLimits() error {
+	if o.quality < 1 || o.quality > 100 {
+		return fmt.Errorf("quality must be from 1 to 100, but got %d", o.quality)
+	}
+	if o.jobs < 1 {
+		return fmt.Errorf("jobs must be 1 or more, but got %d", o.jobs)
+	}
 	return nil
 }
 
 func (o *RootCommandOption) Run(streams IOStreams, currentTime time.Time) error {
-	ffa, err := fontfamily.LoadFromDir(o.fontDir)
+	r, err := o.load(streams)
 	if err != nil {
 		return err
 	}
+	return o.generate(streams, r, o.files, currentTime)
+}
+
+// loadSite loads the site configuration. The drawing configuration file is never the site configuration.
+// It returns nil if the default site directory has no configuration, and writes the warning if the
+// configuration of the default site directory is invalid, because it may not be the Hugo site.
+func (o *RootCommandOption) loadSite(w io.Writer) (*hugo.SiteConfig, error) {
+	dir := o.siteDir
+	if dir == "" {
+		dir = "."
+	}
+	var ignores []string
+	if o.config != "" {
+		ignores = append(ignores, o.config)
+	}
+	site, err := hugo.LoadSiteConfig(dir, ignores...)
+	if o.siteDir == "" && err != nil {
+		if !errors.Is(err, hugo.ErrSiteConfigNotFound) {
+			fmt.Fprintf(w, "WARN: ignore the site configuration: %v\n", err)
+		}
+		return nil, nil
+	}
+	return site, err
+}
+
+// resources are loaded once and shared by the workers as read-only.
+type resources struct {
+	renderer *tcardgen.Renderer
+	cnf      *config.DrawingConfig
+	// site is the site configuration, or nil if it is not found
+	site *hugo.SiteConfig
+	// fontDir is the directory of the fonts
+	fontDir string
+	// digest of the drawing configuration, template image, and fonts
+	digest string
+	// base is the digest of the inputs which are shared by all cards
+	base     string
+	loadedAt time.Time
+	cache    *cardCache
+	// langs are the resources of the languages which have the language configuration
+	langs map[string]*resources
+	// loc is the time zone of the drawing configuration, or nil if it is not set
+	loc *time.Location
+}
+
+// load loads fonts, site configuration, drawing configuration, template image, and cache manifest.
+// The resources of each language are loaded with the language configuration.
+func (o *RootCommandOption) load(streams IOStreams) (*resources, error) {
+	ffa, err := fontfamily.LoadFromDir(o.fontDir)
+	if err != nil {
+		return nil, err
+	}
 	fmt.Fprintf(streams.Out, "Load fonts from %q\n", o.fontDir)
+	fonts := map[string]*fontfamily.FontFamily{o.fontDir: ffa}
+
+	site, err := o.loadSite(streams.ErrOut)
+	if err != nil {
+		return nil, err
+	}
+	if site != nil {
+		fmt.Fprintf(streams.Out, "Load site configuration from %q\n", filepath.Clean(o.siteDir))
+	}
 
 	cnf := &config.DrawingConfig{}
 	if o.config != "" {
 		cnf, err = config.LoadConfig(o.config)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
-	config.Defaulting(cnf, o.tplImg)
-
-	tpl, err := canvas.LoadFromFile(cnf.Template)
+	loc, err := cnf.Location()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if site != nil {
+		// the taxonomies of the site are the default keys of the category and tags
+		if cnf.FrontMatter == nil {
+			cnf.FrontMatter = &hugo.FieldKeys{}
+		}
+		cnf.FrontMatter.Complement(site.FieldKeys())
+	}
+	// the language configurations are overlaid before defaulting, which builds the default templates
+	lcnfs := make(map[string]*config.DrawingConfig, len(cnf.Languages))
+	for lang, lc := range cnf.Languages {
+		if lcnfs[lang], err = cnf.ForLanguage(lc); err != nil {
+			return nil, err
+		}
 	}
-	fmt.Fprintf(streams.Out, "Load template from %q directory\n", cnf.Template)
 
-	outDir, outFilename := filepath.Split(o.output)
-	if o.output == defaultOutput && o.outDir != "" {
-		fmt.Fprint(streams.Out, "\nWarning: This flag will be removed in the future. Please use \"--output\".\n\n")
-		outDir = o.outDir
+	r, err := o.newResources(streams, cnf, o.fontDir, o.tplImg, site, fonts)
+	if err != nil {
+		return nil, err
+	}
+	r.loc = loc
+	if o.cache != "" {
+		if r.cache, err = newCardCache(o.cache, o.force); err != nil {
+			return nil, err
+		}
 	}
 
-	if _, err := os.Stat(outDir); os.IsNotExist(err) {
-		err := os.Mkdir(outDir, 0755)
+	langs := make([]string, 0, len(lcnfs))
+	for lang := range lcnfs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	r.langs = make(map[string]*resources, len(langs))
+	for _, lang := range langs {
+		lc := cnf.Languages[lang]
+		fontDir, tplImg := o.fontDir, o.tplImg
+		if lc.FontDir != "" {
+			fontDir = lc.FontDir
+		}
+		if lc.Template != "" {
+			// the template of the language takes precedence over the flag
+			tplImg = ""
+		}
+		fmt.Fprintf(streams.Out, "Load %q language configuration\n", lang)
+		lr, err := o.newResources(streams, lcnfs[lang], fontDir, tplImg, site, fonts)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("failed to load %q language configuration: %w", lang, err)
 		}
+		lr.cache, lr.loc = r.cache, loc
+		r.langs[strings.ToLower(lang)] = lr
 	}
+	return r, nil
+}
 
-	var errCnt int
-	for _, f := range o.files {
-		out := filepath.Join(outDir, outFilename)
-		if outFilename == "" {
-			base := filepath.Base(f)
-			out += fmt.Sprintf("/%s.png", base[:len(base)-len(filepath.Ext(base))])
+// newResources loads the template image and fonts of the configuration. Loaded fonts are shared by the directories.
+func (o *RootCommandOption) newResources(streams IOStreams, cnf *config.DrawingConfig, fontDir, tplImg string,
+	site *hugo.SiteConfig, fonts map[string]*fontfamily.FontFamily) (*resources, error) {
+	ffa, ok := fonts[fontDir]
+	if !ok {
+		var err error
+		if ffa, err = fontfamily.LoadFromDir(fontDir); err != nil {
+			return nil, err
 		}
+		fmt.Fprintf(streams.Out, "Load fonts from %q\n", fontDir)
+		fonts[fontDir] = ffa
+	}
 
-		if err := generateTCard(streams, f, out, tpl, ffa, cnf, currentTime); err != nil {
-			fmt.Fprintf(streams.ErrOut, "Failed to generate twitter card for %v: %v\n", out, err)
-			errCnt++
-			continue
-		}
-		fmt.Fprintf(streams.Out, "Success to generate twitter card into %v\n", out)
+	config.Defaulting(cnf, tplImg)
+	tpl, err := canvas.LoadFromFile(cnf.Template)
+	if err != nil {
+		return nil, err
 	}
+	fmt.Fprintf(streams.Out, "Load template from %q directory\n", cnf.Template)
 
-	if errCnt != 0 {
-		return fmt.Errorf("failed to generate %d twitter cards", errCnt)
+	digest, err := resourcesDigest(cnf, site, fontDir)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return &resources{
+		renderer: tcardgen.NewRenderer(cnf, ffa, tpl),
+		cnf:      cnf,
+		site:     site,
+		fontDir:  fontDir,
+		digest:   digest,
+		base:     baseDigest(digest, o.outFormat, o.quality),
+		loadedAt: time.Now(),
+	}, nil
 }
 
-func generateTCard(streams IOStreams, contentPath, outPath string, tpl image.Image, ffa *fontfamily.FontFamily, cnf *config.DrawingConfig, currentTime time.Time) error {
-	fm, err := hugo.ParseFrontMatter(streams.Out, contentPath, currentTime)
-	if err != nil {
-		return err
+// forLang returns the resources of the language. The base language such as "ja" of "ja-JP" is also
+// looked up, and the default resources are returned if the language has no configuration.
+func (r *resources) forLang(lang string) *resources {
+	lang = strings.ToLower(lang)
+	if lr, ok := r.langs[lang]; ok {
+		return lr
 	}
+	if lr, ok := r.langs[locale.Base(lang)]; ok {
+		return lr
+	}
+	return r
+}
 
-	c, err := canvas.CreateCanvasFromImage(tpl)
-	if err != nil {
-		return err
+// parseOptions returns the options to parse the front matter with the loaded configurations.
+func (r *resources) parseOptions() []hugo.ParseOption {
+	return []hugo.ParseOption{
+		hugo.WithFieldKeys(*r.cnf.FrontMatter), hugo.WithSiteConfig(r.site), hugo.WithLocation(r.location()),
+		hugo.WithReadingSpeed(*r.cnf.ReadingSpeed), hugo.WithDescriptionLength(r.cnf.DescriptionLength),
+		hugo.WithLanguages(r.languages()...),
 	}
+}
 
-	var tags []string
-	lim := len(fm.Tags)
-	if l := cnf.Tags.Limit; l > 0 && l <= lim {
-		lim = l
+// languages returns the languages which have the language configuration.
+func (r *resources) languages() []string {
+	langs := make([]string, 0, len(r.langs))
+	for lang := range r.langs {
+		langs = append(langs, lang)
 	}
+	return langs
+}
 
-	for _, t := range fm.Tags[:lim] {
-		if *cnf.Tags.TitleCaseEnabled {
-			t = strings.Title(t)
-		}
-		tags = append(tags, t)
+// location returns the time zone of the drawing configuration or site, or nil if it is not configured.
+func (r *resources) location() *time.Location {
+	if r.loc != nil {
+		return r.loc
+	}
+	if r.site != nil && r.site.TimeZone != "" {
+		return r.site.Location()
 	}
+	return nil
+}
 
-	/* Title */
-	if err := c.DrawTextAtPoint(
-		fm.Title,
-		*cnf.Title.Start,
-		canvas.MaxWidth(cnf.Title.MaxWidth),
-		canvas.LineSpacing(*cnf.Title.LineSpacing),
-		canvas.FgHexColor(cnf.Title.FgHexColor),
-		canvas.FontFaceFromFFA(ffa, cnf.Title.FontStyle, cnf.Title.FontSize),
-	); err != nil {
+// generate generates the cards of the files concurrently.
+func (o *RootCommandOption) generate(streams IOStreams, r *resources, files []contentFile, currentTime time.Time) error {
+	if o.output == defaultOutput && o.outDir != "" {
+		fmt.Fprint(streams.Out, "\nWarning: This flag will be removed in the future. Please use \"--output\".\n\n")
+	}
+
+	outs := make([]string, len(files))
+	var (
+		skipped                    = make([]bool, len(files))
+		renderCnt, skipCnt, errCnt int
+	)
+	runJobs(len(files), o.jobs, func(i int, log io.Writer) error {
+		s := IOStreams{Out: log, ErrOut: log}
+		var err error
+		outs[i], skipped[i], err = o.generateTCard(s, files[i], r, currentTime)
 		return err
+	}, func(i int, log []byte, err error) {
+		streams.Out.Write(log)
+		switch {
+		case err != nil:
+			fmt.Fprintf(streams.ErrOut, "Failed to generate twitter card for %v: %v\n", files[i].Path, err)
+			errCnt++
+		case skipped[i]:
+			fmt.Fprintf(streams.Out, "Skip generating twitter card into %v because it is up to date\n", outs[i])
+			skipCnt++
+		default:
+			fmt.Fprintf(streams.Out, "Success to generate twitter card into %v\n", outs[i])
+			renderCnt++
+		}
+	})
+
+	if r.cache != nil {
+		if err := r.cache.manifest.Save(); err != nil {
+			return fmt.Errorf("failed to save cache manifest %q: %w", o.cache, err)
+		}
 	}
-	/* Category */
-	if *cnf.Category.Enabled {
-		if err := c.DrawTextAtPoint(
-			strings.ToUpper(fm.Category),
-			*cnf.Category.Start,
-			canvas.FgHexColor(cnf.Category.FgHexColor),
-			canvas.FontFaceFromFFA(ffa, cnf.Category.FontStyle, cnf.Category.FontSize),
-		); err != nil {
-			return err
+	fmt.Fprintf(streams.Out, "Generated %d, skipped %d, and failed %d twitter cards\n", renderCnt, skipCnt, errCnt)
+
+	if errCnt != 0 {
+		return fmt.Errorf("failed to generate %d twitter cards", errCnt)
+	}
+	return nil
+}
+
+// generateTCard generates the card of the content and returns the output path. If the cache is specified
+// and the inputs of the card are not changed, it skips generating the card and returns true.
+// The front matter is updated even if the card is skipped.
+func (o *RootCommandOption) generateTCard(streams IOStreams, f contentFile, r *resources, currentTime time.Time) (string, bool, error) {
+	fm, err := hugo.ParseFrontMatter(streams.Out, f.Path, currentTime, r.parseOptions()...)
+	if err != nil {
+		return "", false, err
+	}
+	r = r.forLang(fm.Lang)
+	opts := o.renderOptions(streams, f)
+
+	var digest string
+	if r.cache != nil || o.isOutputTemplate() {
+		dfm := fm
+		if o.writeFrontMatter {
+			dfm = withoutParam(fm, o.frontMatterKey)
+		}
+		if digest, err = cardDigest(r.base, dfm, r.renderer, opts...); err != nil {
+			return "", false, err
 		}
 	}
-	/* Info */
-	if *cnf.Info.Enabled {
-		if err := c.DrawTextAtPoint(
-			fmt.Sprintf("%s%s%s", fm.Author, cnf.Info.Separator, fm.Date.Format(cnf.Info.TimeFormat)),
-			*cnf.Info.Start,
-			canvas.FgHexColor(cnf.Info.FgHexColor),
-			canvas.FontFaceFromFFA(ffa, cnf.Info.FontStyle, cnf.Info.FontSize),
-		); err != nil {
-			return err
+	outPath, err := o.outputPath(f, fm, digest)
+	if err != nil {
+		return "", false, err
+	}
+	skipped := r.cache != nil && !r.cache.force && r.cache.manifest.Unchanged(outPath, digest)
+	if !skipped {
+		if err := o.writeTCard(fm, r, outPath, opts); err != nil {
+			return "", false, err
+		}
+		if r.cache != nil {
+			r.cache.manifest.Set(outPath, digest)
 		}
 	}
-	/* Tags */
-	if *cnf.Tags.Enabled {
-		if err := c.DrawBoxTexts(
-			tags,
-			*cnf.Tags.Start,
-			canvas.FgHexColor(cnf.Tags.FgHexColor),
-			canvas.BgHexColor(cnf.Tags.BgHexColor),
-			canvas.BoxPadding(*cnf.Tags.BoxPadding),
-			canvas.BoxSpacing(*cnf.Tags.BoxSpacing),
-			canvas.BoxAlign(cnf.Tags.BoxAlign),
-			canvas.FontFaceFromFFA(ffa, cnf.Tags.FontStyle, cnf.Tags.FontSize),
-		); err != nil {
-			return err
+	if o.writeFrontMatter {
+		if err := o.updateFrontMatter(streams, f.Path, fm, outPath); err != nil {
+			return "", false, fmt.Errorf("failed to write front matter: %w", err)
 		}
 	}
+	return outPath, skipped, nil
+}
+
+// renderOptions returns the options to render the card of the content.
+func (o *RootCommandOption) renderOptions(streams IOStreams, f contentFile) []tcardgen.RenderOption {
+	return []tcardgen.RenderOption{
+		tcardgen.WithContentPath(f.Path), tcardgen.WithStaticDir(o.staticDir),
+		tcardgen.WithQuality(o.quality), tcardgen.WithWarnings(streams.Out),
+	}
+}
 
-	return c.SaveAsPNG(outPath)
+func (o *RootCommandOption) writeTCard(fm *hugo.FrontMatter, r *resources, outPath string, opts []tcardgen.RenderOption) error {
+	// render the card before creating the file not to leave a broken file
+	var buf bytes.Buffer
+	if err := r.renderer.RenderTo(context.Background(), &buf, fm, o.outFormat, opts...); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(outPath, buf.Bytes(), 0644)
 }
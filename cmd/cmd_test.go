@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// chdir changes the working directory to the dir during the test.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestLoadSiteIgnoresInvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"port": 8080`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, dir)
+
+	// the current directory may not be the site, so the invalid configuration is a warning
+	var buf bytes.Buffer
+	site, err := (&RootCommandOption{}).loadSite(&buf)
+	if err != nil || site != nil {
+		t.Errorf("want no site, but got %v, %v", site, err)
+	}
+	if !strings.Contains(buf.String(), "WARN:") {
+		t.Errorf("want warning, but got %q", buf.String())
+	}
+
+	// the site directory is specified, so it must have the valid configuration
+	if _, err := (&RootCommandOption{siteDir: dir}).loadSite(&buf); err == nil {
+		t.Error("want error for the invalid site configuration")
+	}
+}
+
+func TestLoadSiteIgnoresDrawingConfig(t *testing.T) {
+	dir := t.TempDir()
+	drawing := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(drawing, []byte("title:\n  start: {px: 123, py: 165}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, dir)
+
+	var buf bytes.Buffer
+	site, err := (&RootCommandOption{config: "config.yaml"}).loadSite(&buf)
+	if err != nil || site != nil {
+		t.Errorf("want no site, but got %v, %v", site, err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("want no warning, but got %q", buf.String())
+	}
+}
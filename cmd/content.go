@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+// contentExts are the file extensions of the Hugo contents which are found in the directories.
+var contentExts = map[string]bool{
+	".md":       true,
+	".markdown": true,
+	".html":     true,
+	".org":      true,
+}
+
+// contentFile is a content file and its path relative to the input directory.
+type contentFile struct {
+	Path string
+	// Rel is the slash-separated path which is used to mirror the source tree in the output directory.
+	// It is the base name of the file when the file is specified directly.
+	Rel string
+}
+
+// contentFilter selects the content files which are found in the directories.
+// The directly specified files are always selected.
+type contentFilter struct {
+	includeIndex bool
+	include      []glob.Glob
+	exclude      []glob.Glob
+}
+
+func newContentFilter(includeIndex bool, include, exclude []string) (*contentFilter, error) {
+	cf := &contentFilter{includeIndex: includeIndex}
+	for _, p := range include {
+		g, err := glob.Compile(p, '/')
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern %q: %w", p, err)
+		}
+		cf.include = append(cf.include, g)
+	}
+	for _, p := range exclude {
+		g, err := glob.Compile(p, '/')
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", p, err)
+		}
+		cf.exclude = append(cf.exclude, g)
+	}
+	return cf, nil
+}
+
+// contentFiles expands the directories of the paths to the content files in them recursively.
+func (cf *contentFilter) contentFiles(paths []string) ([]contentFile, error) {
+	var files []contentFile
+	for _, p := range paths {
+		p = filepath.Clean(p)
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			files = append(files, contentFile{Path: p, Rel: filepath.Base(p)})
+			continue
+		}
+		err = filepath.WalkDir(p, func(file string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(p, file)
+			if err != nil {
+				return err
+			}
+			if rel = filepath.ToSlash(rel); cf.match(rel) {
+				files = append(files, contentFile{Path: file, Rel: rel})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no content files are found")
+	}
+	return files, nil
+}
+
+// match reports whether the content file of the relative path is selected.
+// Section pages (_index.*) are skipped unless they are included explicitly.
+func (cf *contentFilter) match(rel string) bool {
+	base := path.Base(rel)
+	if !contentExts[path.Ext(base)] {
+		return false
+	}
+	if !cf.includeIndex && strings.HasPrefix(base, "_index.") {
+		return false
+	}
+	if len(cf.include) != 0 && !matchGlobs(cf.include, rel) {
+		return false
+	}
+	return !matchGlobs(cf.exclude, rel)
+}
+
+// matchGlobs reports whether one of the patterns matches the relative path.
+// A pattern without a slash also matches the file name in any directory.
+func matchGlobs(globs []glob.Glob, rel string) bool {
+	base := path.Base(rel)
+	for _, g := range globs {
+		if g.Match(rel) || g.Match(base) {
+			return true
+		}
+	}
+	return false
+}
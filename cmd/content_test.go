@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestContentFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{
+		"_index.md",
+		"about.md",
+		"notes.txt",
+		"posts/2024/foo.md",
+		"posts/2024/draft-bar.markdown",
+		"posts/_index.en.md",
+		"notes/foo.org",
+		"notes/baz.html",
+	} {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		includeIndex bool
+		include      []string
+		exclude      []string
+		expect       []string
+	}{
+		{
+			name:   "all contents",
+			expect: []string{"about.md", "notes/baz.html", "notes/foo.org", "posts/2024/draft-bar.markdown", "posts/2024/foo.md"},
+		},
+		{
+			name:         "include section pages",
+			includeIndex: true,
+			expect:       []string{"_index.md", "about.md", "notes/baz.html", "notes/foo.org", "posts/2024/draft-bar.markdown", "posts/2024/foo.md", "posts/_index.en.md"},
+		},
+		{
+			name:    "include posts",
+			include: []string{"posts/**"},
+			expect:  []string{"posts/2024/draft-bar.markdown", "posts/2024/foo.md"},
+		},
+		{
+			name:    "exclude drafts by file name",
+			exclude: []string{"draft-*", "notes/*.html"},
+			expect:  []string{"about.md", "notes/foo.org", "posts/2024/foo.md"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cf, err := newContentFilter(tt.includeIndex, tt.include, tt.exclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files, err := cf.contentFiles([]string{dir})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range files {
+				if f.Path != filepath.Join(dir, filepath.FromSlash(f.Rel)) {
+					t.Errorf("relative path %q does not match %q", f.Rel, f.Path)
+				}
+				got = append(got, f.Rel)
+			}
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("unexpected files:\n  want: %v\n  got:  %v", tt.expect, got)
+			}
+		})
+	}
+
+	t.Run("specified file", func(t *testing.T) {
+		cf := &contentFilter{}
+		// the specified files are selected even if they are filtered out in directories
+		files, err := cf.contentFiles([]string{filepath.Join(dir, "notes.txt"), filepath.Join(dir, "posts", "_index.en.md")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []contentFile{
+			{Path: filepath.Join(dir, "notes.txt"), Rel: "notes.txt"},
+			{Path: filepath.Join(dir, "posts", "_index.en.md"), Rel: "_index.en.md"},
+		}
+		if !reflect.DeepEqual(files, want) {
+			t.Errorf("unexpected files:\n  want: %v\n  got:  %v", want, files)
+		}
+	})
+
+	if _, err := (&contentFilter{}).contentFiles([]string{filepath.Join(dir, "none")}); err == nil {
+		t.Error("expected an error for the missing path")
+	}
+	if _, err := newContentFilter(false, []string{"[a"}, nil); err == nil {
+		t.Error("expected an error for the invalid pattern")
+	}
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gohugoio/hugo/parser/pageparser"
+
+	"github.com/Ladicle/tcardgen/pkg/hugo"
+)
+
+const (
+	defaultFrontMatterKey = "images"
+	defaultStaticDir      = "static"
+)
+
+// frontMatterRef returns the reference to the card which is written in the front matter. The card in
+// the same directory as the content is a page resource, and the card in the static directory is served
+// from the site root. The other cards have no URL, so it returns an error.
+func frontMatterRef(outPath, contentPath, staticDir string) (string, error) {
+	if filepath.Dir(outPath) == filepath.Dir(contentPath) {
+		return filepath.Base(outPath), nil
+	}
+	if rel, err := filepath.Rel(staticDir, outPath); err == nil && filepath.IsLocal(rel) {
+		return "/" + filepath.ToSlash(rel), nil
+	}
+	return "", fmt.Errorf("cannot write %q into the front matter: the card must be in the static directory %q or the page bundle", outPath, staticDir)
+}
+
+// frontMatterValue returns the new value of the front matter key which refers to the card, or false
+// if the current value already refers to it. The reference is added to the head of a list because
+// Hugo uses the first image for the cards, and the "images" key is a list as Hugo expects.
+func frontMatterValue(key string, current interface{}, ref string) (interface{}, bool) {
+	switch v := current.(type) {
+	case []interface{}:
+		items := []string{ref}
+		for _, item := range v {
+			s := fmt.Sprint(item)
+			if s == ref {
+				return nil, false
+			}
+			items = append(items, s)
+		}
+		return items, true
+	case string:
+		if v == ref {
+			return nil, false
+		}
+		return ref, true
+	}
+	if key == defaultFrontMatterKey || strings.HasSuffix(key, "."+defaultFrontMatterKey) {
+		return []string{ref}, true
+	}
+	return ref, true
+}
+
+// validateFrontMatterOutput returns an error if the front matter cannot refer to the cards of the files.
+// The paths from the output template are unknown until the cards are generated, so they are checked
+// when the front matter is written.
+func (o *RootCommandOption) validateFrontMatterOutput(files []contentFile) error {
+	if o.isOutputTemplate() {
+		return nil
+	}
+	for _, f := range files {
+		outPath, err := o.outputPath(f, nil, "")
+		if err != nil {
+			return err
+		}
+		if _, err := frontMatterRef(outPath, f.Path, o.staticDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateFrontMatter writes the reference to the card into the front matter of the content.
+// The content is not written if the front matter already refers to the card. The current value is
+// read only from the page, so that the site parameter is not copied into the content.
+func (o *RootCommandOption) updateFrontMatter(streams IOStreams, contentPath string, fm *hugo.FrontMatter, outPath string) error {
+	ref, err := frontMatterRef(outPath, contentPath, o.staticDir)
+	if err != nil {
+		return err
+	}
+	value, changed := frontMatterValue(o.frontMatterKey, fm.PageParam(o.frontMatterKey), ref)
+	if !changed {
+		return nil
+	}
+	fi, err := os.Stat(contentPath)
+	if err != nil {
+		return err
+	}
+	b, err := os.ReadFile(contentPath)
+	if err != nil {
+		return err
+	}
+	updated, err := hugo.SetFrontMatterValue(b, o.frontMatterKey, value)
+	if err != nil {
+		return err
+	}
+	if err := checkFrontMatter(b, updated, o.frontMatterKey, value); err != nil {
+		return err
+	}
+	if err := os.WriteFile(contentPath, updated, fi.Mode().Perm()); err != nil {
+		return err
+	}
+	fmt.Fprintf(streams.Out, "Write %q to %q of the front matter in %v\n", ref, o.frontMatterKey, contentPath)
+	return nil
+}
+
+// checkFrontMatter parses the updated content, and returns an error if the key does not have the value
+// or the other values are changed, so that the broken front matter is never written.
+func checkFrontMatter(content, updated []byte, key string, value interface{}) error {
+	before, err := pageparser.ParseFrontMatterAndContent(bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	after, err := pageparser.ParseFrontMatterAndContent(bytes.NewReader(updated))
+	if err != nil {
+		return fmt.Errorf("the updated front matter is invalid: %w", err)
+	}
+	// compare in JSON not to distinguish []string from []interface{}
+	expect, err := json.Marshal(setParam(before.FrontMatter, strings.Split(key, "."), value))
+	if err != nil {
+		return err
+	}
+	got, err := json.Marshal(after.FrontMatter)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(expect, got) {
+		return fmt.Errorf("the front matter is not updated as expected: want %s, but got %s", expect, got)
+	}
+	return nil
+}
+
+// setParam returns a copy of the params which has the value at the keys.
+func setParam(params map[string]interface{}, keys []string, value interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(params)+1)
+	for k, v := range params {
+		c[k] = v
+	}
+	if len(keys) == 1 {
+		c[keys[0]] = value
+	} else {
+		child, _ := c[keys[0]].(map[string]interface{})
+		c[keys[0]] = setParam(child, keys[1:], value)
+	}
+	return c
+}
+
+// withoutParam returns a copy of the front matter without the (dotted) key, so that the value
+// which is written by tcardgen does not change the digest of the card.
+func withoutParam(fm *hugo.FrontMatter, key string) *hugo.FrontMatter {
+	c := *fm
+	c.Params = deleteParam(fm.Params, strings.Split(key, "."))
+	return &c
+}
+
+func deleteParam(params map[string]interface{}, keys []string) map[string]interface{} {
+	if _, ok := params[keys[0]]; !ok {
+		return params
+	}
+	c := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		c[k] = v
+	}
+	if len(keys) == 1 {
+		delete(c, keys[0])
+	} else if child, ok := c[keys[0]].(map[string]interface{}); ok {
+		c[keys[0]] = deleteParam(child, keys[1:])
+	}
+	return c
+}
+
+// validateFrontMatterKey checks the key which is written in the front matter.
+func validateFrontMatterKey(key string) error {
+	for _, k := range strings.Split(key, ".") {
+		if k == "" || strings.ContainsAny(k, ` "'[]{}:=#`) {
+			return fmt.Errorf("invalid front matter key %q", key)
+		}
+	}
+	return nil
+}
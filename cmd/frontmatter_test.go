@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Ladicle/tcardgen/pkg/canvas"
+	"github.com/Ladicle/tcardgen/pkg/hugo"
+	"github.com/Ladicle/tcardgen/pkg/manifest"
+)
+
+func TestFrontMatterRef(t *testing.T) {
+	tests := []struct {
+		out, content string
+		expect       string
+		wantErr      bool
+	}{
+		{out: "static/og/foo.png", content: "content/posts/foo.md", expect: "/og/foo.png"},
+		{out: "content/posts/foo/featured.png", content: "content/posts/foo/index.md", expect: "featured.png"},
+		{out: "out/foo.png", content: "content/posts/foo.md", wantErr: true},
+		{out: "static-old/foo.png", content: "content/posts/foo.md", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := frontMatterRef(filepath.FromSlash(tt.out), filepath.FromSlash(tt.content), "static")
+		if (err != nil) != tt.wantErr || got != tt.expect {
+			t.Errorf("%s: want %q (error %v), but got %q (%v)", tt.out, tt.expect, tt.wantErr, got, err)
+		}
+	}
+}
+
+func TestValidateFrontMatterOutput(t *testing.T) {
+	files := []contentFile{
+		{Path: filepath.FromSlash("content/posts/foo.md"), Rel: "posts/foo.md"},
+		{Path: filepath.FromSlash("content/posts/bar/index.md"), Rel: "posts/bar/index.md"},
+	}
+	tests := []struct {
+		desc    string
+		opt     RootCommandOption
+		files   []contentFile
+		wantErr bool
+	}{
+		{desc: "static", opt: RootCommandOption{output: "static/og/"}, files: files},
+		{desc: "outside static", opt: RootCommandOption{output: "out/"}, files: files, wantErr: true},
+		{desc: "bundle", opt: RootCommandOption{output: "out/", bundle: true, bundleName: "featured"}, files: files[1:]},
+		{desc: "not bundle content", opt: RootCommandOption{output: "out/", bundle: true, bundleName: "featured"}, files: files, wantErr: true},
+		{desc: "template", opt: RootCommandOption{output: "out/{{ .Slug }}.png"}, files: files},
+	}
+	for _, tt := range tests {
+		tt.opt.outFormat, tt.opt.staticDir = canvas.PNG, "static"
+		if err := tt.opt.validateFrontMatterOutput(tt.files); (err != nil) != tt.wantErr {
+			t.Errorf("%s: want error %v, but got %v", tt.desc, tt.wantErr, err)
+		}
+	}
+}
+
+func TestFrontMatterValue(t *testing.T) {
+	tests := []struct {
+		desc    string
+		key     string
+		current interface{}
+		expect  interface{}
+	}{
+		{desc: "new images", key: "images", expect: []string{"/og/foo.png"}},
+		{desc: "new nested images", key: "params.images", expect: []string{"/og/foo.png"}},
+		{desc: "new string", key: "params.og", expect: "/og/foo.png"},
+		{desc: "add to list", key: "images", current: []interface{}{"/cover.png"}, expect: []string{"/og/foo.png", "/cover.png"}},
+		{desc: "in list", key: "images", current: []interface{}{"/cover.png", "/og/foo.png"}},
+		{desc: "replace string", key: "featured_image", current: "/old.png", expect: "/og/foo.png"},
+		{desc: "same string", key: "featured_image", current: "/og/foo.png"},
+	}
+	for _, tt := range tests {
+		got, changed := frontMatterValue(tt.key, tt.current, "/og/foo.png")
+		if changed != (tt.expect != nil) || !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("%s: want %v, but got %v (changed=%v)", tt.desc, tt.expect, got, changed)
+		}
+	}
+}
+
+func TestCheckFrontMatter(t *testing.T) {
+	content := "---\ntitle: Foo\nimages: [/cover.png]\n---\nbody\n"
+	tests := []struct {
+		desc    string
+		updated string
+		key     string
+		value   interface{}
+		wantErr bool
+	}{
+		{desc: "updated", updated: "---\ntitle: Foo\nimages: [/og/foo.png, /cover.png]\n---\nbody\n", key: "images", value: []string{"/og/foo.png", "/cover.png"}},
+		{desc: "nested key", updated: "---\ntitle: Foo\nimages: [/cover.png]\nparams:\n  og: /og/foo.png\n---\nbody\n", key: "params.og", value: "/og/foo.png"},
+		{desc: "not updated", updated: content, key: "images", value: []string{"/og/foo.png", "/cover.png"}, wantErr: true},
+		{desc: "other value changed", updated: "---\ntitle: Bar\nimages: [/og/foo.png, /cover.png]\n---\nbody\n", key: "images", value: []string{"/og/foo.png", "/cover.png"}, wantErr: true},
+		{desc: "other value removed", updated: "---\nimages: [/og/foo.png, /cover.png]\n---\nbody\n", key: "images", value: []string{"/og/foo.png", "/cover.png"}, wantErr: true},
+		{desc: "invalid", updated: "---\ntitle: [Foo\n---\nbody\n", key: "title", value: "Foo", wantErr: true},
+	}
+	for _, tt := range tests {
+		err := checkFrontMatter([]byte(content), []byte(tt.updated), tt.key, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: want error %v, but got %v", tt.desc, tt.wantErr, err)
+		}
+	}
+}
+
+func TestGenerateWritesFrontMatter(t *testing.T) {
+	dir := t.TempDir()
+	content := filepath.Join(dir, "content", "posts")
+	if err := os.MkdirAll(content, 0755); err != nil {
+		t.Fatal(err)
+	}
+	post := filepath.Join(content, "hello.md")
+	src := "---\ntitle: Hello\nauthor: Ladicle\ncategories: [blog]\ntags: [go]\ndate: 2024-01-02\n---\nbody\n"
+	if err := os.WriteFile(post, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := newTestResources(t)
+	m, err := manifest.Load(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.cache = &cardCache{manifest: m}
+	o := &RootCommandOption{
+		output:           filepath.Join(dir, "static", "og", "{{ .Slug }}.{{ .Hash }}.{{ .Ext }}"),
+		outFormat:        canvas.PNG,
+		quality:          canvas.DefaultJPEGQuality,
+		jobs:             1,
+		writeFrontMatter: true,
+		frontMatterKey:   "images",
+		staticDir:        filepath.Join(dir, "static"),
+	}
+	streams := IOStreams{Out: io.Discard, ErrOut: io.Discard}
+	files := []contentFile{{Path: post, Rel: "posts/hello.md"}}
+
+	if err := o.generate(streams, r, files, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	first, err := os.ReadFile(post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cards, _ := filepath.Glob(filepath.Join(dir, "static", "og", "hello.*.png"))
+	if len(cards) != 1 {
+		t.Fatalf("want 1 card, but got %v", cards)
+	}
+	expect := "---\ntitle: Hello\nauthor: Ladicle\ncategories: [blog]\ntags: [go]\ndate: 2024-01-02\nimages: [\"/og/" + filepath.Base(cards[0]) + "\"]\n---\nbody\n"
+	if string(first) != expect {
+		t.Fatalf("want %q, but got %q", expect, first)
+	}
+
+	// the written key does not change the card, so the second run changes nothing
+	_, skipped, err := o.generateTCard(streams, files[0], r, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !skipped {
+		t.Error("the card must be skipped in the second run")
+	}
+	second, err := os.ReadFile(post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(second) != string(first) {
+		t.Errorf("front matter must not be changed in the second run, but got %q", second)
+	}
+}
+
+func TestGenerateWritesFrontMatterWithoutSiteParams(t *testing.T) {
+	dir := t.TempDir()
+	content := filepath.Join(dir, "content", "posts")
+	if err := os.MkdirAll(content, 0755); err != nil {
+		t.Fatal(err)
+	}
+	post := filepath.Join(content, "hello.md")
+	src := "---\ntitle: Hello\nauthor: Ladicle\ncategories: [blog]\ntags: [go]\ndate: 2024-01-02\n---\nbody\n"
+	if err := os.WriteFile(post, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := newTestResources(t)
+	r.site = &hugo.SiteConfig{Params: map[string]interface{}{"images": []interface{}{"/img/site-default.png"}}}
+	m, err := manifest.Load(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.cache = &cardCache{manifest: m}
+	o := &RootCommandOption{
+		output:           filepath.Join(dir, "static", "og", "{{ .Slug }}.{{ .Hash }}.{{ .Ext }}"),
+		outFormat:        canvas.PNG,
+		quality:          canvas.DefaultJPEGQuality,
+		jobs:             1,
+		writeFrontMatter: true,
+		frontMatterKey:   "images",
+		staticDir:        filepath.Join(dir, "static"),
+	}
+	streams := IOStreams{Out: io.Discard, ErrOut: io.Discard}
+	files := []contentFile{{Path: post, Rel: "posts/hello.md"}}
+
+	if err := o.generate(streams, r, files, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cards, _ := filepath.Glob(filepath.Join(dir, "static", "og", "hello.*.png"))
+	if len(cards) != 1 {
+		t.Fatalf("want 1 card, but got %v", cards)
+	}
+	// the site images are used only to render the card, and are not copied into the post
+	expect := "---\ntitle: Hello\nauthor: Ladicle\ncategories: [blog]\ntags: [go]\ndate: 2024-01-02\nimages: [\"/og/" + filepath.Base(cards[0]) + "\"]\n---\nbody\n"
+	if string(got) != expect {
+		t.Errorf("want %q, but got %q", expect, got)
+	}
+}
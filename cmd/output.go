@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ladicle/tcardgen/pkg/canvas"
+	"github.com/Ladicle/tcardgen/pkg/hugo"
+	"github.com/Ladicle/tcardgen/pkg/tmpl"
+)
+
+// hashLength is the length of the hash in the output filename.
+const hashLength = 16
+
+// outputData is the data of the output filename template.
+type outputData struct {
+	*hugo.FrontMatter
+	// Section is the first directory of the content which is relative to the input directory.
+	Section string
+	// Slug is the slug in the front matter, the bundle name, or the filename without the extension.
+	Slug string
+	// Lang is the language code in the filename.
+	Lang string
+	// Dir is the directory of the content which is relative to the input directory.
+	Dir string
+	// Name is the filename without the language code and extension.
+	Name string
+	// Year, Month, and Day are the zero-padded parts of the date.
+	Year, Month, Day string
+	// Hash is the digest of the inputs of the card.
+	Hash string
+	// Ext is the extension of the output format without the dot.
+	Ext string
+}
+
+func newOutputData(f contentFile, fm *hugo.FrontMatter, digest string, format canvas.Format) *outputData {
+	// the code in the filename is the language if it is detected as the language of the content
+	name, lang := hugo.SplitLang(path.Base(f.Rel), func(code string) bool {
+		return strings.EqualFold(code, fm.Lang)
+	})
+	dir := path.Dir(f.Rel)
+
+	// the section of a specified file is guessed from its directory,
+	// and the directory of a leaf bundle is the page itself
+	section := filepath.Dir(f.Path)
+	if name == "index" {
+		section = filepath.Dir(section)
+	}
+	section = filepath.Base(section)
+	if i := strings.Index(f.Rel, "/"); i > 0 {
+		section = f.Rel[:i]
+	}
+
+	slug, _ := fm.Param("slug").(string)
+	switch {
+	case slug != "":
+	case name == "index" || name == "_index":
+		// the bundle directory is the name of the page
+		slug = filepath.Base(filepath.Dir(f.Path))
+	default:
+		slug = name
+	}
+
+	if len(digest) > hashLength {
+		digest = digest[:hashLength]
+	}
+	return &outputData{
+		FrontMatter: fm,
+		Section:     section,
+		Slug:        slug,
+		Lang:        lang,
+		Dir:         dir,
+		Name:        name,
+		Year:        fmt.Sprintf("%04d", fm.Date.Year()),
+		Month:       fmt.Sprintf("%02d", fm.Date.Month()),
+		Day:         fmt.Sprintf("%02d", fm.Date.Day()),
+		Hash:        digest,
+		Ext:         strings.TrimPrefix(format.Ext(), "."),
+	}
+}
+
+// isOutputTemplate reports whether the output is the filename template.
+func (o *RootCommandOption) isOutputTemplate() bool {
+	return strings.Contains(o.output, "{{")
+}
+
+// outputPath returns the path of the card of the content. The output paths of the files
+// in the directories mirror the source tree unless the output is a filename or template.
+func (o *RootCommandOption) outputPath(f contentFile, fm *hugo.FrontMatter, digest string) (string, error) {
+	if p, ok := o.bundlePath(f.Path); ok {
+		return p, nil
+	}
+	if o.isOutputTemplate() {
+		p, err := tmpl.Execute(o.output, newOutputData(f, fm, digest, o.outFormat))
+		if err != nil {
+			return "", fmt.Errorf("failed to execute output template: %w", err)
+		}
+		return filepath.Clean(filepath.FromSlash(p)), nil
+	}
+
+	outDir, outFilename := filepath.Split(o.output)
+	if o.output == defaultOutput && o.outDir != "" {
+		outDir = o.outDir
+	}
+	if outFilename != "" {
+		return filepath.Join(outDir, outFilename), nil
+	}
+	rel := filepath.FromSlash(f.Rel)
+	return filepath.Join(outDir, rel[:len(rel)-len(filepath.Ext(rel))]+o.outFormat.Ext()), nil
+}
+
+// bundlePath returns the card path in the bundle directory if the content is the index of a page bundle.
+// The language code of the content is kept, so that "index.ja.md" gets "featured.ja.png".
+func (o *RootCommandOption) bundlePath(contentPath string) (string, bool) {
+	if !o.bundle {
+		return "", false
+	}
+	base := filepath.Base(contentPath)
+	stem := strings.TrimPrefix(strings.TrimSuffix(base, filepath.Ext(base)), "_")
+	// lang is "" or the language code with the dot such as ".ja"
+	lang, ok := strings.CutPrefix(stem, "index")
+	if !ok || (lang != "" && lang[0] != '.') {
+		return "", false
+	}
+	return filepath.Join(filepath.Dir(contentPath), o.bundleName+lang+o.outFormat.Ext()), true
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Ladicle/tcardgen/pkg/canvas"
+	"github.com/Ladicle/tcardgen/pkg/hugo"
+)
+
+func TestBundlePath(t *testing.T) {
+	o := &RootCommandOption{bundle: true, bundleName: "featured", outFormat: canvas.PNG}
+	tests := []struct {
+		content string
+		expect  string
+	}{
+		{content: "posts/foo/index.md", expect: "posts/foo/featured.png"},
+		{content: "posts/_index.md", expect: "posts/featured.png"},
+		{content: "posts/foo/index.ja.md", expect: "posts/foo/featured.ja.png"},
+		{content: "posts/_index.en.html", expect: "posts/featured.en.png"},
+		{content: "posts/foo.md"},
+		{content: "posts/indexes.md"},
+		{content: "posts/_foo.md"},
+	}
+	for _, tt := range tests {
+		got, ok := o.bundlePath(filepath.FromSlash(tt.content))
+		if ok != (tt.expect != "") || got != filepath.FromSlash(tt.expect) {
+			t.Errorf("%s: want %q, but got %q (bundle=%v)", tt.content, tt.expect, got, ok)
+		}
+	}
+
+	o.bundle = false
+	if _, ok := o.bundlePath("posts/foo/index.md"); ok {
+		t.Error("bundle path must not be used when bundle mode is disabled")
+	}
+}
+
+func TestOutputPath(t *testing.T) {
+	fm := &hugo.FrontMatter{
+		Title:  "Hello",
+		Date:   time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC),
+		Params: map[string]interface{}{"slug": "hello-world"},
+	}
+	tests := []struct {
+		output string
+		file   contentFile
+		fm     *hugo.FrontMatter
+		expect string
+	}{
+		{
+			output: "out/",
+			file:   contentFile{Path: "content/posts/2024/foo.md", Rel: "posts/2024/foo.md"},
+			expect: "out/posts/2024/foo.png",
+		},
+		{
+			output: "out/card.png",
+			file:   contentFile{Path: "example/blog-post.md", Rel: "blog-post.md"},
+			expect: "out/card.png",
+		},
+		{
+			output: "static/og/{{ .Section }}/{{ .Slug }}-{{ .Lang }}.{{ .Ext }}",
+			file:   contentFile{Path: "content/posts/foo.ja.md", Rel: "posts/foo.ja.md"},
+			fm:     &hugo.FrontMatter{Lang: "ja"},
+			expect: "static/og/posts/foo-ja.png",
+		},
+		{
+			output: "og/{{ .Slug }}{{ with .Lang }}.{{ . }}{{ end }}.png",
+			file:   contentFile{Path: "content/posts/node.js.md", Rel: "posts/node.js.md"},
+			expect: "og/node.js.png",
+		},
+		{
+			output: "og/{{ .Year }}/{{ .Month }}/{{ .Day }}/{{ .Slug }}.png",
+			file:   contentFile{Path: "content/posts/foo.md", Rel: "posts/foo.md"},
+			fm:     fm,
+			expect: "og/2024/03/09/hello-world.png",
+		},
+		{
+			output: "og/{{ .Section }}/{{ .Slug }}{{ with .Lang }}.{{ . }}{{ end }}.png",
+			file:   contentFile{Path: "example/bundle/index.md", Rel: "index.md"},
+			expect: "og/example/bundle.png",
+		},
+		{
+			output: "og/{{ .Dir }}/{{ .Name }}-{{ .Hash }}.png",
+			file:   contentFile{Path: "content/posts/2024/foo.md", Rel: "posts/2024/foo.md"},
+			expect: "og/posts/2024/foo-0123456789abcdef.png",
+		},
+	}
+	for _, tt := range tests {
+		o := &RootCommandOption{output: tt.output, outFormat: canvas.PNG}
+		fm := tt.fm
+		if fm == nil {
+			fm = &hugo.FrontMatter{}
+		}
+		got, err := o.outputPath(tt.file, fm, "0123456789abcdef0123")
+		if err != nil {
+			t.Errorf("%s: %v", tt.output, err)
+			continue
+		}
+		if got != filepath.FromSlash(tt.expect) {
+			t.Errorf("%s: want %q, but got %q", tt.output, tt.expect, got)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Ladicle/tcardgen/pkg/canvas"
+	"github.com/Ladicle/tcardgen/pkg/hugo"
+	"github.com/Ladicle/tcardgen/pkg/tcardgen"
+)
+
+const (
+	defaultServeAddr = "localhost:8080"
+
+	serveExample = `# Preview images on http://localhost:8080 while you tune the drawing configuration.
+tcardgen serve --config=example/template3.config.yaml example/`
+)
+
+func NewServeCmd(opt *RootCommandOption) *cobra.Command {
+	var addr string
+	cmd := &cobra.Command{
+		Use:                   "serve [--addr <ADDR>] [-f <FONTDIR>] [-t <TEMPLATE>] [-c <CONFIG>] [<FILE|DIR>...]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Preview images on a local server which reloads them when the inputs are changed.",
+		Example:               serveExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			streams := IOStreams{
+				Out:    os.Stdout,
+				ErrOut: os.Stderr,
+			}
+			if err := opt.Validate(cmd, args); err != nil {
+				return err
+			}
+			// cards are rendered in memory, so they are never up to date
+			opt.cache = ""
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			return opt.Serve(ctx, streams, addr)
+		},
+	}
+	cmd.Flags().StringVarP(&addr, "addr", "", defaultServeAddr, "Set an address to listen on.")
+	return cmd
+}
+
+// Serve serves the preview pages of the cards of the paths until the context is done.
+// The pages are reloaded by server-sent events whenever the inputs are changed.
+func (o *RootCommandOption) Serve(ctx context.Context, streams IOStreams, addr string) error {
+	r, err := o.load(streams)
+	if err != nil {
+		return err
+	}
+	wt, err := o.newWatchTargets(r, o.paths)
+	if err != nil {
+		return err
+	}
+	defer wt.close()
+
+	ps := &previewServer{
+		streams:   streams,
+		paths:     o.paths,
+		filter:    o.filter,
+		quality:   o.quality,
+		staticDir: o.staticDir,
+		r:         r,
+		clients:   map[chan struct{}]struct{}{},
+	}
+	laddr, shutdown, err := startHTTPServer(ctx, addr, ps.handler())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(streams.Out, "Serving previews on http://%s/. Press Ctrl+C to stop.\n", laddr)
+
+	err = wt.watch(ctx, streams, func(reload bool, files []string) {
+		if reload {
+			fmt.Fprintln(streams.Out, "Reload the configuration")
+			nr, err := o.reload(streams, wt)
+			if err != nil {
+				fmt.Fprintf(streams.ErrOut, "Failed to reload: %v\n", err)
+				return
+			}
+			ps.setResources(nr)
+		}
+		ps.notify()
+	})
+
+	if serr := shutdown(); err == nil {
+		err = serr
+	}
+	return err
+}
+
+// startHTTPServer starts serving the handler on the address until the returned function is called.
+// Requests are canceled when the context is done, so that long-lived connections do not block the shutdown.
+func startHTTPServer(ctx context.Context, addr string, h http.Handler) (net.Addr, func() error, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	srv := &http.Server{
+		Handler:     h,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+	return ln.Addr(), func() error {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err := srv.Shutdown(shutdownCtx)
+		if serr := <-errCh; !errors.Is(serr, http.ErrServerClosed) && err == nil {
+			err = serr
+		}
+		return err
+	}, nil
+}
+
+// previewServer renders the cards on demand with the latest resources.
+type previewServer struct {
+	streams IOStreams
+	paths   []string
+	filter  *contentFilter
+	quality int
+	// staticDir is the static directory of the site which has the images from the site root
+	staticDir string
+
+	mu sync.RWMutex
+	r  *resources
+
+	clientsMu sync.Mutex
+	clients   map[chan struct{}]struct{}
+}
+
+func (ps *previewServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", ps.handleIndex)
+	mux.HandleFunc("GET /card/{path...}", ps.handleCard)
+	mux.HandleFunc("GET /events", ps.handleEvents)
+	return mux
+}
+
+func (ps *previewServer) resources() *resources {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	return ps.r
+}
+
+func (ps *previewServer) setResources(r *resources) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.r = r
+}
+
+// preview is a post which is listed in the index page.
+type preview struct {
+	// Path is the path of the card which is relative to the content directory.
+	Path  string
+	File  string
+	Title string
+	Err   error
+}
+
+// previews returns the posts of the paths. The card path of a post in a directory is
+// relative to the directory, and that of a specified file is the base name of the file.
+func (ps *previewServer) previews() ([]preview, error) {
+	files, err := ps.filter.contentFiles(ps.paths)
+	if err != nil {
+		return nil, err
+	}
+	pvs := make([]preview, len(files))
+	for i, f := range files {
+		pvs[i] = preview{Path: strings.TrimSuffix(f.Rel, path.Ext(f.Rel)), File: f.Path}
+	}
+	return pvs, nil
+}
+
+func (ps *previewServer) handleIndex(w http.ResponseWriter, req *http.Request) {
+	pvs, err := ps.previews()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	r := ps.resources()
+	for i := range pvs {
+		fm, err := hugo.ParseFrontMatter(io.Discard, pvs[i].File, time.Now(), r.parseOptions()...)
+		if err != nil {
+			pvs[i].Err = err
+			continue
+		}
+		pvs[i].Title = fm.Title
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := indexTemplate.Execute(w, pvs); err != nil {
+		fmt.Fprintf(ps.streams.ErrOut, "Failed to write the index page: %v\n", err)
+	}
+}
+
+func (ps *previewServer) handleCard(w http.ResponseWriter, req *http.Request) {
+	p := req.PathValue("path")
+	format, ok := canvas.FormatFromExt(p)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	pvs, err := ps.previews()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, pv := range pvs {
+		if pv.Path != strings.TrimSuffix(p, filepath.Ext(p)) {
+			continue
+		}
+		r := ps.resources()
+		fm, err := hugo.ParseFrontMatter(ps.streams.Out, pv.File, time.Now(), r.parseOptions()...)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		opts := []tcardgen.RenderOption{
+			tcardgen.WithContentPath(pv.File), tcardgen.WithStaticDir(ps.staticDir),
+			tcardgen.WithQuality(ps.quality), tcardgen.WithWarnings(ps.streams.Out),
+		}
+		var buf bytes.Buffer
+		if err := r.forLang(fm.Lang).renderer.RenderTo(req.Context(), &buf, fm, format, opts...); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", format.MIMEType())
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(buf.Bytes())
+		return
+	}
+	http.NotFound(w, req)
+}
+
+// handleEvents sends a reload event to the page whenever the inputs are changed.
+func (ps *previewServer) handleEvents(w http.ResponseWriter, req *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	ch := ps.subscribe()
+	defer ps.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-ch:
+			fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func (ps *previewServer) subscribe() chan struct{} {
+	ch := make(chan struct{}, 1)
+	ps.clientsMu.Lock()
+	ps.clients[ch] = struct{}{}
+	ps.clientsMu.Unlock()
+	return ch
+}
+
+func (ps *previewServer) unsubscribe(ch chan struct{}) {
+	ps.clientsMu.Lock()
+	delete(ps.clients, ch)
+	ps.clientsMu.Unlock()
+}
+
+// notify notifies all pages of the change. Pending notifications are merged into one.
+func (ps *previewServer) notify() {
+	ps.clientsMu.Lock()
+	defer ps.clientsMu.Unlock()
+	for ch := range ps.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>tcardgen preview</title>
+<style>
+body { font-family: sans-serif; margin: 2em; background: #f5f5f5; }
+.cards { display: grid; grid-template-columns: repeat(auto-fill, minmax(480px, 1fr)); gap: 2em; }
+.card img { width: 100%; box-shadow: 0 1px 4px rgba(0, 0, 0, .3); }
+.card p { margin: .5em 0; word-break: break-all; }
+.error { color: #c00; }
+</style>
+</head>
+<body>
+<div class="cards">
+{{- range . }}
+<div class="card">
+  <p><a href="card/{{ .Path }}.png">{{ .File }}</a></p>
+  {{- if .Err }}
+  <p class="error">{{ .Err }}</p>
+  {{- else }}
+  <img src="card/{{ .Path }}.png" alt="{{ .Title }}" loading="lazy">
+  {{- end }}
+</div>
+{{- end }}
+</div>
+<script>
+new EventSource("events").addEventListener("reload", () => location.reload());
+</script>
+</body>
+</html>
+`))
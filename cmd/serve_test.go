@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ladicle/tcardgen/internal/fonttest"
+	"github.com/Ladicle/tcardgen/pkg/canvas"
+	"github.com/Ladicle/tcardgen/pkg/config"
+	"github.com/Ladicle/tcardgen/pkg/tcardgen"
+)
+
+// newTestResources returns the resources with Go fonts and the example template.
+func newTestResources(t *testing.T) *resources {
+	t.Helper()
+	cnf := &config.DrawingConfig{}
+	config.Defaulting(cnf, "../example/template.png")
+	tpl, err := canvas.LoadFromFile(cnf.Template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &resources{renderer: tcardgen.NewRenderer(cnf, fonttest.GoFonts(), tpl), cnf: cnf}
+}
+
+func TestPreviewServer(t *testing.T) {
+	dir := t.TempDir()
+	post, err := os.ReadFile("../example/blog-post.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "post.md"), post, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.md"), []byte("---\ntitle: [\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ps := &previewServer{
+		streams: IOStreams{Out: io.Discard, ErrOut: io.Discard},
+		paths:   []string{dir},
+		filter:  &contentFilter{},
+		r:       newTestResources(t),
+		clients: map[chan struct{}]struct{}{},
+	}
+	srv := httptest.NewServer(ps.handler())
+	defer srv.Close()
+
+	t.Run("index", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		for _, want := range []string{`<img src="card/sub/post.png"`, `class="error"`, `new EventSource("events")`} {
+			if !strings.Contains(string(body), want) {
+				t.Errorf("index page does not contain %q:\n%s", want, body)
+			}
+		}
+	})
+
+	t.Run("card", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "/card/sub/post.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "image/png" {
+			t.Fatalf("unexpected response: status=%d, type=%q", res.StatusCode, res.Header.Get("Content-Type"))
+		}
+		img, err := png.Decode(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if img.Bounds() != ps.r.renderer.Bounds() {
+			t.Errorf("unexpected bounds: %v", img.Bounds())
+		}
+	})
+
+	for path, want := range map[string]int{
+		"/card/none.png":     http.StatusNotFound,
+		"/card/sub/post.gif": http.StatusNotFound,
+		"/card/broken.png":   http.StatusInternalServerError,
+	} {
+		res, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != want {
+			t.Errorf("%s: want status %d, but got %d", path, want, res.StatusCode)
+		}
+	}
+
+	t.Run("events", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "/events")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		ps.notify()
+		buf := make([]byte, len("event: reload\n"))
+		if _, err := io.ReadFull(res.Body, buf); err != nil {
+			t.Fatal(err)
+		}
+		if string(buf) != "event: reload\n" {
+			t.Errorf("unexpected event: %q", buf)
+		}
+	})
+}
@@ -0,0 +1,393 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Ladicle/tcardgen/pkg/canvas"
+	"github.com/Ladicle/tcardgen/pkg/config"
+	"github.com/Ladicle/tcardgen/pkg/hugo"
+	"github.com/Ladicle/tcardgen/pkg/tcardgen"
+	"github.com/Ladicle/tcardgen/pkg/tmpl"
+)
+
+const (
+	defaultServerAddr      = ":8080"
+	defaultCacheEntries    = 256
+	defaultMaxRequestBytes = 64 << 10
+
+	serverExample = `# Render images on demand on port 8080.
+tcardgen server --config=example/template3.config.yaml
+
+# Get an image of the front matter.
+curl -o card.png "http://localhost:8080/render?title=Hello&author=Ladicle&tags=go,hugo&date=2024-01-02"
+
+# Get a JPEG image of the front matter in JSON.
+curl -o card.jpg -d '{"title": "Hello", "tags": ["go", "hugo"]}' "http://localhost:8080/render?format=jpeg"`
+)
+
+type serverOption struct {
+	addr            string
+	cacheEntries    int
+	maxRequestBytes int64
+}
+
+func NewServerCmd(opt *RootCommandOption) *cobra.Command {
+	so := serverOption{}
+	cmd := &cobra.Command{
+		Use:                   "server [--addr <ADDR>] [-f <FONTDIR>] [-t <TEMPLATE>] [-c <CONFIG>]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Render images on demand with the HTTP API.",
+		Long: `Render images on demand with the HTTP API.
+
+  GET  /render?title=...&author=...&category=...&tags=...&description=...&date=...&lastmod=...&lang=...
+  POST /render with the front matter in JSON
+
+The output format is selected by the "format" parameter (default is --format or png).`,
+		Example: serverExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			streams := IOStreams{
+				Out:    os.Stdout,
+				ErrOut: os.Stderr,
+			}
+			if err := opt.validateServer(so); err != nil {
+				return err
+			}
+			// rendered images are cached in memory instead
+			opt.cache = ""
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			return opt.RunServer(ctx, streams, so)
+		},
+	}
+	cmd.Flags().StringVarP(&so.addr, "addr", "", defaultServerAddr, "Set an address to listen on.")
+	cmd.Flags().IntVarP(&so.cacheEntries, "cache-entries", "", defaultCacheEntries, "Set the number of rendered images which are cached in memory.")
+	cmd.Flags().Int64VarP(&so.maxRequestBytes, "max-request-bytes", "", defaultMaxRequestBytes, "Set the maximum size of a query string or request body.")
+	return cmd
+}
+
+func (o *RootCommandOption) validateServer(so serverOption) error {
+	o.outFormat = canvas.PNG
+	if o.format != "" {
+		f, err := canvas.ParseFormat(o.format)
+		if err != nil {
+			return err
+		}
+		o.outFormat = f
+	}
+	if so.cacheEntries < 0 {
+		return fmt.Errorf("cache-entries must be 0 or more, but got %d", so.cacheEntries)
+	}
+	if so.maxRequestBytes < 1 {
+		return fmt.Errorf("max-request-bytes must be 1 or more, but got %d", so.maxRequestBytes)
+	}
+	return o.validateLimits()
+}
+
+// RunServer serves the render API until the context is done.
+// Fonts and template image are loaded once, and shared by all requests.
+func (o *RootCommandOption) RunServer(ctx context.Context, streams IOStreams, so serverOption) error {
+	r, err := o.load(streams)
+	if err != nil {
+		return err
+	}
+	rs := &renderServer{
+		r:               r,
+		format:          o.outFormat,
+		quality:         o.quality,
+		maxRequestBytes: so.maxRequestBytes,
+		cache:           newLRUCache(so.cacheEntries),
+		sem:             make(chan struct{}, o.jobs),
+	}
+	laddr, shutdown, err := startHTTPServer(ctx, so.addr, rs.handler())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(streams.Out, "Serving the render API on %s. Press Ctrl+C to stop.\n", laddr)
+	<-ctx.Done()
+	return shutdown()
+}
+
+// renderServer renders the cards of the front matters in the requests.
+type renderServer struct {
+	r               *resources
+	format          canvas.Format
+	quality         int
+	maxRequestBytes int64
+	cache           *lruCache
+	// sem limits the number of concurrent renderings
+	sem chan struct{}
+}
+
+func (rs *renderServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /render", rs.handleGet)
+	mux.HandleFunc("POST /render", rs.handlePost)
+	return mux
+}
+
+func (rs *renderServer) handleGet(w http.ResponseWriter, req *http.Request) {
+	if int64(len(req.URL.RawQuery)) > rs.maxRequestBytes {
+		http.Error(w, "query string is too long", http.StatusRequestURITooLong)
+		return
+	}
+	fm, err := frontMatterFromQuery(req.URL.Query(), rs.now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rs.render(w, req, fm, req.URL.Query().Get("date") != "")
+}
+
+func (rs *renderServer) handlePost(w http.ResponseWriter, req *http.Request) {
+	req.Body = http.MaxBytesReader(w, req.Body, rs.maxRequestBytes)
+	body := postFrontMatter{FrontMatter: &hugo.FrontMatter{}}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			http.Error(w, "request body is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, fmt.Sprintf("failed to decode the front matter: %v", err), http.StatusBadRequest)
+		return
+	}
+	fm, err := body.frontMatter(rs.now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rs.render(w, req, fm, body.Date != "")
+}
+
+// postFrontMatter is the front matter in the body of the POST request. The dates are strings to parse
+// them in the same formats as the query parameters.
+type postFrontMatter struct {
+	*hugo.FrontMatter
+	Date        string
+	Lastmod     string
+	PublishDate string
+}
+
+// frontMatter returns the front matter whose dates are parsed. The date is today if it is empty,
+// and the last modified and published dates are the date if they are empty.
+func (p postFrontMatter) frontMatter(currentTime time.Time) (*hugo.FrontMatter, error) {
+	fm := p.FrontMatter
+	var err error
+	if fm.Date, err = parseDate(p.Date, today(currentTime)); err != nil {
+		return nil, err
+	}
+	if fm.Lastmod, err = parseDate(p.Lastmod, fm.Date); err != nil {
+		return nil, err
+	}
+	if fm.PublishDate, err = parseDate(p.PublishDate, fm.Date); err != nil {
+		return nil, err
+	}
+	return fm, nil
+}
+
+// render writes the card of the front matter, which is dated if the request has the date. The card is
+// not rendered if the client has the same one, and the rendered card is cached by the ETag.
+func (rs *renderServer) render(w http.ResponseWriter, req *http.Request, fm *hugo.FrontMatter, dated bool) {
+	format := rs.format
+	if name := req.URL.Query().Get("format"); name != "" {
+		f, err := canvas.ParseFormat(name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		format = f
+	}
+	fm.SetSite(rs.r.site)
+	if loc := rs.r.location(); loc != nil {
+		fm.SetLocation(loc)
+	}
+	if err := checkImageFields(rs.r.cnf, fm); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// the card which has the relative date is changed every day as the card without the date
+	data, err := rs.r.forLang(fm.Lang).renderer.CardData(fm)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, relative := data[tmpl.Today]
+	modtime := rs.lastModified(dated && !relative)
+	etag, err := rs.etag(fm, format, modtime)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", modtime.UTC().Format(http.TimeFormat))
+	if notModified(req, etag, modtime) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	b, ok := rs.cache.Get(etag)
+	if !ok {
+		if b, err = rs.renderImage(req.Context(), fm, format); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rs.cache.Add(etag, b)
+	}
+	w.Header().Set("Content-Type", format.MIMEType())
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	w.Write(b)
+}
+
+func (rs *renderServer) renderImage(ctx context.Context, fm *hugo.FrontMatter, format canvas.Format) ([]byte, error) {
+	select {
+	case rs.sem <- struct{}{}:
+		defer func() { <-rs.sem }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	var buf bytes.Buffer
+	if err := rs.r.forLang(fm.Lang).renderer.RenderTo(ctx, &buf, fm, format, tcardgen.WithQuality(rs.quality)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// lastModified returns the modification time of the card. The card which is not dated is changed at
+// the start of each day.
+func (rs *renderServer) lastModified(dated bool) time.Time {
+	if t := today(rs.now()); !dated && t.After(rs.r.loadedAt) {
+		return t
+	}
+	return rs.r.loadedAt
+}
+
+// etag returns the strong ETag of the card which is rendered from the front matter at the modification time.
+func (rs *renderServer) etag(fm *hugo.FrontMatter, format canvas.Format, modtime time.Time) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s:%d:%d\n", rs.r.forLang(fm.Lang).digest, format, rs.quality, modtime.Unix())
+	if err := json.NewEncoder(h).Encode(fm); err != nil {
+		return "", err
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil))[:32] + `"`, nil
+}
+
+// notModified reports whether the client has the card of the ETag or modification time.
+func notModified(req *http.Request, etag string, modtime time.Time) bool {
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		for _, t := range strings.Split(inm, ",") {
+			t = strings.TrimSpace(t)
+			if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := req.Header.Get("If-Modified-Since"); ims != "" {
+		t, err := http.ParseTime(ims)
+		return err == nil && !modtime.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// parseDate parses the date of the request in the location of the default date, or returns the
+// default date if it is empty.
+func parseDate(d string, def time.Time) (time.Time, error) {
+	if d == "" {
+		return def, nil
+	}
+	t, err := hugo.ParseTime(d, def.Location())
+	if err != nil {
+		return def, fmt.Errorf("invalid date %q: %w", d, err)
+	}
+	return t, nil
+}
+
+// frontMatterFromQuery returns the front matter of the query parameters. Tags are separated by commas,
+// and all parameters are stored in the params to refer to them as custom fields.
+func frontMatterFromQuery(q map[string][]string, currentTime time.Time) (*hugo.FrontMatter, error) {
+	get := func(key string) string {
+		if vs := q[key]; len(vs) != 0 {
+			return vs[0]
+		}
+		return ""
+	}
+	fm := &hugo.FrontMatter{
+		Title:       get("title"),
+		Author:      get("author"),
+		Category:    get("category"),
+		Description: get("description"),
+		Lang:        get("lang"),
+		Date:        today(currentTime),
+		Params:      map[string]interface{}{},
+	}
+	for _, v := range q["tags"] {
+		for _, tag := range strings.Split(v, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				fm.Tags = append(fm.Tags, tag)
+			}
+		}
+	}
+	var err error
+	if fm.Date, err = parseDate(get("date"), fm.Date); err != nil {
+		return nil, err
+	}
+	if fm.Lastmod, err = parseDate(get("lastmod"), fm.Date); err != nil {
+		return nil, err
+	}
+	fm.PublishDate = fm.Date
+	for k := range q {
+		if k != "format" {
+			fm.Params[k] = get(k)
+		}
+	}
+	fm.Params["tags"] = fm.Tags
+	return fm, nil
+}
+
+// checkImageFields rejects the image fields which refer to files outside the image directories,
+// because requests must not read arbitrary files on the server.
+func checkImageFields(cnf *config.DrawingConfig, fm *hugo.FrontMatter) error {
+	for _, e := range cnf.Elements {
+		if e.Type != config.ElementImage || e.Image.Field == "" {
+			continue
+		}
+		p, _ := fm.Param(e.Image.Field).(string)
+		if p == "" {
+			continue
+		}
+		if e.Image.Dir == "" || !filepath.IsLocal(p) {
+			return fmt.Errorf("image field %q must be a relative path in the image directory", e.Image.Field)
+		}
+	}
+	return nil
+}
+
+// now returns the current time in the configured time zone.
+func (rs *renderServer) now() time.Time {
+	if loc := rs.r.location(); loc != nil {
+		return time.Now().In(loc)
+	}
+	return time.Now()
+}
+
+// today returns the start of the day, so that the default date does not change the ETag within a day.
+func today(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"encoding/json"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ladicle/tcardgen/pkg/config"
+	"github.com/Ladicle/tcardgen/pkg/hugo"
+)
+
+func TestLRUCache(t *testing.T) {
+	c := newLRUCache(2)
+	c.Add("a", []byte("a"))
+	c.Add("b", []byte("b"))
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a must be cached")
+	}
+	// b is the least recently used
+	c.Add("c", []byte("c"))
+	if _, ok := c.Get("b"); ok {
+		t.Error("b must be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if v, ok := c.Get(key); !ok || string(v) != key {
+			t.Errorf("%s must be cached, but got %q", key, v)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("want 2 entries, but got %d", c.Len())
+	}
+}
+
+func TestFrontMatterFromQuery(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	q, _ := url.ParseQuery("title=Hello&author=Ladicle&tags=go,+hugo&tags=ogp&cover=a.png&format=jpeg")
+	fm, err := frontMatterFromQuery(q, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fm.Title != "Hello" || fm.Author != "Ladicle" || !reflect.DeepEqual(fm.Tags, []string{"go", "hugo", "ogp"}) {
+		t.Errorf("unexpected front matter: %+v", fm)
+	}
+	if !fm.Date.Equal(time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("date must be the start of today, but got %v", fm.Date)
+	}
+	if fm.Param("cover") != "a.png" || fm.Param("format") != nil {
+		t.Errorf("unexpected params: %v", fm.Params)
+	}
+
+	q, _ = url.ParseQuery("date=2021-02-03")
+	if fm, err = frontMatterFromQuery(q, now); err != nil || !fm.Date.Equal(time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected date: %v, %v", fm, err)
+	}
+	q, _ = url.ParseQuery("date=2021-02-03&lastmod=2021-03-04")
+	if fm, err = frontMatterFromQuery(q, now); err != nil || !fm.Updated() || !fm.PublishDate.Equal(fm.Date) {
+		t.Errorf("unexpected lastmod: %v, %v", fm, err)
+	}
+	jst := time.FixedZone("JST", 9*60*60)
+	q, _ = url.ParseQuery("date=2021-02-03T10:00:00")
+	if fm, err = frontMatterFromQuery(q, now.In(jst)); err != nil || !fm.Date.Equal(time.Date(2021, 2, 3, 10, 0, 0, 0, jst)) {
+		t.Errorf("date must be in the time zone: %v, %v", fm, err)
+	}
+	q, _ = url.ParseQuery("date=yesterday")
+	if _, err := frontMatterFromQuery(q, now); err == nil {
+		t.Error("expected an error for the invalid date")
+	}
+}
+
+func TestPostFrontMatter(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	for _, tc := range []struct {
+		body                   string
+		date, lastmod, publish time.Time
+		wantErr                bool
+	}{
+		{
+			body: `{"title": "Hello"}`,
+			date: time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), lastmod: time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), publish: time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			body: `{"title": "Hello", "date": "2021-02-03", "lastmod": "2021-03-04T05:06:07Z"}`,
+			date: time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC), lastmod: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), publish: time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{body: `{"date": "yesterday"}`, wantErr: true},
+	} {
+		body := postFrontMatter{FrontMatter: &hugo.FrontMatter{}}
+		if err := json.Unmarshal([]byte(tc.body), &body); err != nil {
+			t.Fatal(err)
+		}
+		fm, err := body.frontMatter(now)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: want error %v, but got %v", tc.body, tc.wantErr, err)
+			continue
+		}
+		if err == nil && (!fm.Date.Equal(tc.date) || !fm.Lastmod.Equal(tc.lastmod) || !fm.PublishDate.Equal(tc.publish)) {
+			t.Errorf("%s: unexpected dates: %v, %v, %v", tc.body, fm.Date, fm.Lastmod, fm.PublishDate)
+		}
+	}
+}
+
+func TestCheckImageFields(t *testing.T) {
+	cnf := &config.DrawingConfig{Elements: []config.Element{
+		{Type: config.ElementImage, Image: &config.ImageOption{Field: "cover", Dir: "static"}},
+		{Type: config.ElementImage, Image: &config.ImageOption{Field: "avatar"}},
+	}}
+	for _, tc := range []struct {
+		params map[string]interface{}
+		valid  bool
+	}{
+		{params: map[string]interface{}{}, valid: true},
+		{params: map[string]interface{}{"cover": "img/a.png"}, valid: true},
+		{params: map[string]interface{}{"cover": "../secret.png"}},
+		{params: map[string]interface{}{"cover": "/etc/a.png"}},
+		{params: map[string]interface{}{"avatar": "a.png"}},
+	} {
+		err := checkImageFields(cnf, &hugo.FrontMatter{Params: tc.params})
+		if (err == nil) != tc.valid {
+			t.Errorf("%v: want valid=%v, but got %v", tc.params, tc.valid, err)
+		}
+	}
+}
+
+func TestRenderServer(t *testing.T) {
+	r := newTestResources(t)
+	r.digest = "test"
+	r.loadedAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rs := &renderServer{
+		r:               r,
+		format:          "png",
+		quality:         90,
+		maxRequestBytes: 256,
+		cache:           newLRUCache(8),
+		sem:             make(chan struct{}, 2),
+	}
+	srv := httptest.NewServer(rs.handler())
+	defer srv.Close()
+
+	do := func(t *testing.T, method, path, body string, header http.Header) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { res.Body.Close() })
+		return res
+	}
+
+	var etag string
+	t.Run("GET", func(t *testing.T) {
+		res := do(t, http.MethodGet, "/render?title=Hello&tags=go,hugo&date=2024-01-02", "", nil)
+		if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "image/png" {
+			t.Fatalf("unexpected response: status=%d, type=%q", res.StatusCode, res.Header.Get("Content-Type"))
+		}
+		img, _, err := image.Decode(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if img.Bounds() != r.renderer.Bounds() {
+			t.Errorf("unexpected bounds: %v", img.Bounds())
+		}
+		etag = res.Header.Get("ETag")
+		if etag == "" || res.Header.Get("Last-Modified") != "Tue, 02 Jan 2024 03:04:05 GMT" {
+			t.Errorf("unexpected cache headers: %v", res.Header)
+		}
+		if rs.cache.Len() != 1 {
+			t.Errorf("the image must be cached")
+		}
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		res := do(t, http.MethodPost, "/render?format=jpeg", `{"title": "Hello", "tags": ["go", "hugo"], "date": "2024-01-02"}`, nil)
+		if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "image/jpeg" {
+			t.Fatalf("unexpected response: status=%d, type=%q", res.StatusCode, res.Header.Get("Content-Type"))
+		}
+		if _, format, err := image.Decode(res.Body); err != nil || format != "jpeg" {
+			t.Fatalf("failed to decode JPEG: format=%q, err=%v", format, err)
+		}
+		if res.Header.Get("ETag") == etag {
+			t.Error("ETag must depend on the format")
+		}
+	})
+
+	t.Run("NotModified", func(t *testing.T) {
+		res := do(t, http.MethodGet, "/render?title=Hello&tags=go,hugo&date=2024-01-02", "", http.Header{"If-None-Match": {etag}})
+		if res.StatusCode != http.StatusNotModified {
+			t.Errorf("want 304 for If-None-Match, but got %d", res.StatusCode)
+		}
+		res = do(t, http.MethodGet, "/render?title=Other&date=2024-01-02", "", http.Header{"If-Modified-Since": {"Tue, 02 Jan 2024 03:04:05 GMT"}})
+		if res.StatusCode != http.StatusNotModified {
+			t.Errorf("want 304 for If-Modified-Since, but got %d", res.StatusCode)
+		}
+		// the card without the date is changed today
+		res = do(t, http.MethodGet, "/render?title=Other", "", http.Header{"If-Modified-Since": {"Tue, 02 Jan 2024 03:04:05 GMT"}})
+		if res.StatusCode != http.StatusOK {
+			t.Errorf("want 200 for the card of today, but got %d", res.StatusCode)
+		}
+		if lm, err := http.ParseTime(res.Header.Get("Last-Modified")); err != nil || !lm.Equal(today(rs.now()).Truncate(time.Second)) {
+			t.Errorf("Last-Modified must be the start of today, but got %q", res.Header.Get("Last-Modified"))
+		}
+	})
+
+	for name, tc := range map[string]struct {
+		method, path, body string
+		want               int
+	}{
+		"long query":        {method: http.MethodGet, path: "/render?title=" + strings.Repeat("a", 300), want: http.StatusRequestURITooLong},
+		"large body":        {method: http.MethodPost, path: "/render", body: `{"title": "` + strings.Repeat("a", 300) + `"}`, want: http.StatusRequestEntityTooLarge},
+		"invalid json":      {method: http.MethodPost, path: "/render", body: `{"title": `, want: http.StatusBadRequest},
+		"invalid format":    {method: http.MethodGet, path: "/render?title=a&format=gif", want: http.StatusBadRequest},
+		"invalid date":      {method: http.MethodGet, path: "/render?date=today", want: http.StatusBadRequest},
+		"invalid body date": {method: http.MethodPost, path: "/render", body: `{"date": "today"}`, want: http.StatusBadRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if res := do(t, tc.method, tc.path, tc.body, nil); res.StatusCode != tc.want {
+				t.Errorf("want status %d, but got %d", tc.want, res.StatusCode)
+			}
+		})
+	}
+}
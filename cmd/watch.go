@@ -0,0 +1,389 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+
+	"github.com/Ladicle/tcardgen/pkg/config"
+)
+
+const (
+	// debounceDuration is the quiet period to wait for the burst of events,
+	// because editors write a file several times when it is saved.
+	debounceDuration = 200 * time.Millisecond
+
+	watchExample = `# Regenerate images whenever the posts, config, template, or fonts are changed.
+tcardgen watch --config=example/template3.config.yaml example/`
+)
+
+func NewWatchCmd(opt *RootCommandOption) *cobra.Command {
+	return &cobra.Command{
+		Use:                   "watch [-f <FONTDIR>] [-o <OUTPUT>] [-t <TEMPLATE>] [-c <CONFIG>] [<FILE|DIR>...]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Regenerate images when the posts, config, template, or fonts are changed.",
+		Example:               watchExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			streams := IOStreams{
+				Out:    os.Stdout,
+				ErrOut: os.Stderr,
+			}
+			if err := opt.Validate(cmd, args); err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			return opt.Watch(ctx, streams)
+		},
+	}
+}
+
+// Watch generates the cards of the paths and then regenerates them whenever the inputs are changed
+// until the context is done. Errors of the regeneration are reported without stopping the watch.
+func (o *RootCommandOption) Watch(ctx context.Context, streams IOStreams) error {
+	r, err := o.load(streams)
+	if err != nil {
+		return err
+	}
+	wt, err := o.newWatchTargets(r, o.paths)
+	if err != nil {
+		return err
+	}
+	defer wt.close()
+
+	o.regenerate(streams, r, nil)
+	fmt.Fprintln(streams.Out, "Watching for changes. Press Ctrl+C to stop.")
+
+	return wt.watch(ctx, streams, func(reload bool, files []string) {
+		if reload {
+			fmt.Fprintln(streams.Out, "\nReload the configuration and regenerate all cards")
+			nr, err := o.reload(streams, wt)
+			if err != nil {
+				fmt.Fprintf(streams.ErrOut, "Failed to reload: %v\n", err)
+				return
+			}
+			r = nr
+			o.regenerate(streams, r, nil)
+			return
+		}
+		o.regenerate(streams, r, files)
+	})
+}
+
+// reload loads the resources again to apply the changes of the config, template, or fonts.
+func (o *RootCommandOption) reload(streams IOStreams, wt *watchTargets) (*resources, error) {
+	r, err := o.load(streams)
+	if err != nil {
+		return nil, err
+	}
+	if err := wt.setResources(r); err != nil {
+		fmt.Fprintf(streams.ErrOut, "Failed to watch the resources: %v\n", err)
+	}
+	return r, nil
+}
+
+// regenerate generates the cards of the contents in the paths again. If the changed files are
+// specified, only their cards are generated, and the files which are not selected are ignored.
+func (o *RootCommandOption) regenerate(streams IOStreams, r *resources, changed []string) {
+	files, err := o.filter.contentFiles(o.paths)
+	if err != nil {
+		o.reportError(streams, err)
+		return
+	}
+	if changed != nil {
+		files = selectFiles(files, changed)
+		if len(files) == 0 {
+			return
+		}
+		fmt.Fprintf(streams.Out, "\nRegenerate the cards of %d changed files\n", len(files))
+	}
+	o.reportError(streams, o.generate(streams, r, files, time.Now()))
+}
+
+// selectFiles returns the content files of the paths.
+func selectFiles(files []contentFile, paths []string) []contentFile {
+	selected := map[string]bool{}
+	for _, p := range paths {
+		selected[filepath.Clean(p)] = true
+	}
+	var ret []contentFile
+	for _, f := range files {
+		if selected[f.Path] {
+			ret = append(ret, f)
+		}
+	}
+	return ret
+}
+
+func (o *RootCommandOption) reportError(streams IOStreams, err error) {
+	if err != nil {
+		fmt.Fprintf(streams.ErrOut, "Error: %v\n", err)
+	}
+}
+
+type changeKind int
+
+const (
+	changeNone changeKind = iota
+	// changeContent changes only the card of the content.
+	changeContent
+	// changeAll changes all cards.
+	changeAll
+)
+
+// watchTargets are the files and directories which are the inputs of the cards.
+type watchTargets struct {
+	w *fsnotify.Watcher
+
+	// inputs are the content files or directories
+	inputs []string
+	config string
+	// files are the template images and the image files of the elements of all languages
+	files map[string]bool
+	// dirs are the font directories and the image directories of the elements of all languages
+	dirs map[string]bool
+	// siteDir is the directory of the site configuration, or empty if it is not found
+	siteDir string
+}
+
+// newWatchTargets starts watching the inputs of the cards. The parent directories of the files
+// are watched instead of the files, because editors replace the file with a new one when it is saved.
+func (o *RootCommandOption) newWatchTargets(r *resources, paths []string) (*watchTargets, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	wt := &watchTargets{
+		w:      w,
+		inputs: paths,
+		config: filepath.Clean(o.config),
+	}
+	if err := wt.setResources(r); err != nil {
+		w.Close()
+		return nil, err
+	}
+
+	var dirs []string
+	if wt.config != "." {
+		dirs = append(dirs, filepath.Dir(wt.config))
+	}
+	if r.site != nil {
+		wt.siteDir = filepath.Clean(o.siteDir)
+		dirs = append(dirs, wt.siteDir)
+		if d := filepath.Join(wt.siteDir, "config", "_default"); isDir(d) {
+			dirs = append(dirs, d)
+		}
+	}
+	for _, p := range wt.inputs {
+		fi, err := os.Stat(p)
+		if err != nil {
+			w.Close()
+			return nil, err
+		}
+		if !fi.IsDir() {
+			dirs = append(dirs, filepath.Dir(p))
+			continue
+		}
+		if err := addDirs(w, p); err != nil {
+			w.Close()
+			return nil, err
+		}
+	}
+	for _, d := range dirs {
+		if err := w.Add(d); err != nil {
+			w.Close()
+			return nil, fmt.Errorf("failed to watch %q: %w", d, err)
+		}
+	}
+	return wt, nil
+}
+
+func (wt *watchTargets) close() error {
+	return wt.w.Close()
+}
+
+// setResources watches the templates, fonts, and images of the resources and their languages, which
+// may be changed by the config. The image directories which do not exist are not watched.
+func (wt *watchTargets) setResources(r *resources) error {
+	all := []*resources{r}
+	for _, lr := range r.langs {
+		all = append(all, lr)
+	}
+	wt.files, wt.dirs = map[string]bool{}, map[string]bool{}
+	for _, lr := range all {
+		wt.files[filepath.Clean(lr.cnf.Template)] = true
+		wt.dirs[filepath.Clean(lr.fontDir)] = true
+		for _, e := range lr.cnf.Elements {
+			if e.Type != config.ElementImage || !config.IsEnabled(e.Image.Enabled) {
+				continue
+			}
+			if e.Image.Path != "" {
+				wt.files[filepath.Clean(e.Image.Path)] = true
+			}
+			if e.Image.Dir != "" {
+				wt.dirs[filepath.Clean(e.Image.Dir)] = true
+			}
+		}
+	}
+
+	dirs := map[string]bool{}
+	for f := range wt.files {
+		dirs[filepath.Dir(f)] = true
+	}
+	for d := range wt.dirs {
+		dirs[d] = true
+	}
+	for d := range dirs {
+		if !isDir(d) {
+			continue
+		}
+		if err := wt.w.Add(d); err != nil {
+			return fmt.Errorf("failed to watch %q: %w", d, err)
+		}
+	}
+	return nil
+}
+
+// watch calls the handler with the changes until the context is done. The burst of events
+// is debounced, and the handler is called with reload or the changed content files.
+func (wt *watchTargets) watch(ctx context.Context, streams IOStreams, handle func(reload bool, files []string)) error {
+	var (
+		reload  bool
+		changed = map[string]bool{}
+		fire    <-chan time.Time
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-wt.w.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(streams.ErrOut, "Failed to watch files: %v\n", err)
+		case ev, ok := <-wt.w.Events:
+			if !ok {
+				return nil
+			}
+			if ev.Op == fsnotify.Chmod {
+				continue
+			}
+			if ev.Has(fsnotify.Create) && wt.inInputDir(ev.Name) {
+				// watch the directory which is created in the content directory
+				if fi, err := os.Stat(ev.Name); err == nil && fi.IsDir() {
+					if err := addDirs(wt.w, ev.Name); err != nil {
+						fmt.Fprintf(streams.ErrOut, "Failed to watch %q: %v\n", ev.Name, err)
+					}
+					// the directory may be moved with its contents
+					reload = true
+					fire = time.After(debounceDuration)
+					continue
+				}
+			}
+			switch wt.classify(ev.Name) {
+			case changeAll:
+				reload = true
+			case changeContent:
+				changed[filepath.Clean(ev.Name)] = true
+			default:
+				continue
+			}
+			fire = time.After(debounceDuration)
+		case <-fire:
+			var files []string
+			for f := range changed {
+				// the file is removed or renamed to another name
+				if _, err := os.Stat(f); err == nil {
+					files = append(files, f)
+				}
+			}
+			sort.Strings(files)
+			if reload || len(files) != 0 {
+				handle(reload, files)
+			}
+			fire, reload, changed = nil, false, map[string]bool{}
+		}
+	}
+}
+
+// classify returns the kind of the change of the file.
+func (wt *watchTargets) classify(name string) changeKind {
+	name = filepath.Clean(name)
+	switch {
+	case name == wt.config || wt.files[name]:
+		return changeAll
+	case wt.dirs[filepath.Dir(name)]:
+		return changeAll
+	case wt.isSiteConfig(name):
+		return changeAll
+	}
+	for _, p := range wt.inputs {
+		if name == filepath.Clean(p) {
+			return changeContent
+		}
+	}
+	if contentExts[filepath.Ext(name)] && wt.inInputDir(name) {
+		return changeContent
+	}
+	return changeNone
+}
+
+// isSiteConfig reports whether the file is one of the site configuration files.
+func (wt *watchTargets) isSiteConfig(name string) bool {
+	if wt.siteDir == "" {
+		return false
+	}
+	switch filepath.Ext(name) {
+	case ".toml", ".yaml", ".yml", ".json":
+	default:
+		return false
+	}
+	if filepath.Dir(name) == filepath.Join(wt.siteDir, "config", "_default") {
+		return true
+	}
+	base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	return filepath.Dir(name) == wt.siteDir && (base == "hugo" || base == "config")
+}
+
+func isDir(name string) bool {
+	fi, err := os.Stat(name)
+	return err == nil && fi.IsDir()
+}
+
+// inInputDir reports whether the file is in one of the input directories.
+func (wt *watchTargets) inInputDir(name string) bool {
+	for _, p := range wt.inputs {
+		if fi, err := os.Stat(p); err != nil || !fi.IsDir() {
+			continue
+		}
+		if rel, err := filepath.Rel(p, name); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// addDirs adds the directory and its subdirectories to the watcher.
+func addDirs(w *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if err := w.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %q: %w", path, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/Ladicle/tcardgen/pkg/config"
+)
+
+func TestWatchTargetsClassify(t *testing.T) {
+	dir := t.TempDir()
+	posts := filepath.Join(dir, "posts")
+	if err := os.Mkdir(posts, 0755); err != nil {
+		t.Fatal(err)
+	}
+	wt := &watchTargets{
+		inputs:  []string{posts, filepath.Join(dir, "about.txt")},
+		config:  filepath.Join(dir, "config.yaml"),
+		files:   map[string]bool{filepath.Join(dir, "template.png"): true},
+		dirs:    map[string]bool{filepath.Join(dir, "font"): true},
+		siteDir: dir,
+	}
+
+	tests := []struct {
+		name string
+		want changeKind
+	}{
+		{name: "config.yaml", want: changeAll},
+		{name: "template.png", want: changeAll},
+		{name: "font/Go-Bold.ttf", want: changeAll},
+		{name: "hugo.toml", want: changeAll},
+		{name: "config/_default/params.yaml", want: changeAll},
+		{name: "posts/hugo.toml", want: changeNone},
+		{name: "posts/a.md", want: changeContent},
+		{name: "posts/sub/b.org", want: changeContent},
+		{name: "about.txt", want: changeContent},
+		{name: "posts/a.md~", want: changeNone},
+		{name: "other.md", want: changeNone},
+		{name: "posts-old/a.md", want: changeNone},
+	}
+	for _, tt := range tests {
+		if got := wt.classify(filepath.Join(dir, tt.name)); got != tt.want {
+			t.Errorf("%s: want %v, but got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestWatchTargetsSetResources(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{"font", "font-ja", "img", "avatars"} {
+		if err := os.Mkdir(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	images := []config.Element{
+		{Type: config.ElementImage, Image: &config.ImageOption{Path: filepath.Join(dir, "img", "logo.png")}},
+		{Type: config.ElementImage, Image: &config.ImageOption{Field: "avatar", Dir: filepath.Join(dir, "avatars")}},
+		{Type: config.ElementImage, Image: &config.ImageOption{Field: "cover", Dir: filepath.Join(dir, "missing")}},
+	}
+	r := &resources{
+		cnf:     &config.DrawingConfig{Template: filepath.Join(dir, "template.png")},
+		fontDir: filepath.Join(dir, "font"),
+		langs: map[string]*resources{
+			"ja": {
+				cnf:     &config.DrawingConfig{Template: filepath.Join(dir, "ja", "template.png"), Elements: images},
+				fontDir: filepath.Join(dir, "font-ja"),
+			},
+		},
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	wt := &watchTargets{w: w}
+	if err := wt.setResources(r); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want changeKind
+	}{
+		{name: "template.png", want: changeAll},
+		{name: "ja/template.png", want: changeAll},
+		{name: "font-ja/Go-Bold.ttf", want: changeAll},
+		{name: "img/logo.png", want: changeAll},
+		{name: "img/other.png", want: changeNone},
+		{name: "avatars/ladicle.png", want: changeAll},
+	}
+	for _, tt := range tests {
+		if got := wt.classify(filepath.Join(dir, tt.name)); got != tt.want {
+			t.Errorf("%s: want %v, but got %v", tt.name, tt.want, got)
+		}
+	}
+	if !slices.Contains(w.WatchList(), filepath.Join(dir, "font-ja")) {
+		t.Errorf("the font directory of the language must be watched: %v", w.WatchList())
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// jobResult is the result of a job which is reported in the order of jobs.
+type jobResult struct {
+	log  bytes.Buffer
+	err  error
+	done chan struct{}
+}
+
+// runJobs runs n jobs on the workers concurrently.
+// Each job writes its log into a buffer, and then report receives the log and error of
+// each job in the order of jobs, so the logs of jobs are not interleaved.
+func runJobs(n, workers int, job func(i int, log io.Writer) error, report func(i int, log []byte, err error)) {
+	results := make([]*jobResult, n)
+	for i := range results {
+		results[i] = &jobResult{done: make(chan struct{})}
+	}
+
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				r := results[i]
+				r.err = job(i, &r.log)
+				close(r.done)
+			}
+		}()
+	}
+	go func() {
+		for i := 0; i < n; i++ {
+			queue <- i
+		}
+		close(queue)
+	}()
+
+	for i, r := range results {
+		<-r.done
+		report(i, r.log.Bytes(), r.err)
+	}
+	wg.Wait()
+}
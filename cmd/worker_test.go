@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestRunJobs(t *testing.T) {
+	const n = 20
+
+	var (
+		order  []int
+		errCnt int
+	)
+	runJobs(n, 4, func(i int, log io.Writer) error {
+		// later jobs finish earlier
+		time.Sleep(time.Duration(n-i) * time.Millisecond)
+		fmt.Fprintf(log, "job %d", i)
+		if i%5 == 0 {
+			return errors.New("failed")
+		}
+		return nil
+	}, func(i int, log []byte, err error) {
+		if string(log) != fmt.Sprintf("job %d", i) {
+			t.Errorf("unexpected log of job %d: %q", i, log)
+		}
+		if err != nil {
+			errCnt++
+		}
+		order = append(order, i)
+	})
+
+	for i, got := range order {
+		if got != i {
+			t.Fatalf("jobs are not reported in order: %v", order)
+		}
+	}
+	if len(order) != n || errCnt != 4 {
+		t.Fatalf("unexpected results: reported=%d, errors=%d", len(order), errCnt)
+	}
+}
@@ -0,0 +1,29 @@
+// Package fonttest provides the Go fonts for the tests, so that they do not depend on font files.
+package fonttest
+
+import (
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomedium"
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/Ladicle/tcardgen/pkg/canvas/fontfamily"
+)
+
+// GoFonts returns the font family of the Go fonts in the regular, medium, and bold styles.
+// It panics if the embedded fonts cannot be parsed.
+func GoFonts() *fontfamily.FontFamily {
+	ffa := fontfamily.NewFontFamily("Go")
+	for style, ttf := range map[fontfamily.Style][]byte{
+		fontfamily.Regular: goregular.TTF,
+		fontfamily.Medium:  gomedium.TTF,
+		fontfamily.Bold:    gobold.TTF,
+	} {
+		f, err := truetype.Parse(ttf)
+		if err != nil {
+			panic(err)
+		}
+		ffa.AddFont(style, f)
+	}
+	return ffa
+}
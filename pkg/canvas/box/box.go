@@ -1,8 +1,79 @@
 package box
 
+import (
+	"encoding/json"
+	"strings"
+)
+
 type Align string
 
 const (
-	AlignLeft  = Align("Left")
-	AlignRight = Align("Right")
+	AlignLeft   = Align("Left")
+	AlignCenter = Align("Center")
+	AlignRight  = Align("Right")
+)
+
+type VerticalAlign string
+
+const (
+	VerticalAlignTop    = VerticalAlign("Top")
+	VerticalAlignMiddle = VerticalAlign("Middle")
+	VerticalAlignBottom = VerticalAlign("Bottom")
+)
+
+type Fit string
+
+const (
+	FitContain = Fit("Contain")
+	FitCover   = Fit("Cover")
+	FitStretch = Fit("Stretch")
 )
+
+type Mask string
+
+const (
+	MaskNone    = Mask("None")
+	MaskCircle  = Mask("Circle")
+	MaskRounded = Mask("Rounded")
+)
+
+// UnmarshalJSON accepts the align value case-insensitively.
+func (a *Align) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, string(AlignLeft), string(AlignCenter), string(AlignRight))
+	*a = Align(s)
+	return err
+}
+
+// UnmarshalJSON accepts the vertical align value case-insensitively.
+func (a *VerticalAlign) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, string(VerticalAlignTop), string(VerticalAlignMiddle), string(VerticalAlignBottom))
+	*a = VerticalAlign(s)
+	return err
+}
+
+// UnmarshalJSON accepts the fit value case-insensitively.
+func (f *Fit) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, string(FitContain), string(FitCover), string(FitStretch))
+	*f = Fit(s)
+	return err
+}
+
+// UnmarshalJSON accepts the mask value case-insensitively.
+func (m *Mask) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, string(MaskNone), string(MaskCircle), string(MaskRounded))
+	*m = Mask(s)
+	return err
+}
+
+func unmarshalEnum(b []byte, values ...string) (string, error) {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return "", err
+	}
+	for _, v := range values {
+		if strings.EqualFold(s, v) {
+			return v, nil
+		}
+	}
+	return s, nil
+}
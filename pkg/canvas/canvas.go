@@ -1,7 +1,6 @@
 package canvas
 
 import (
-	"bytes"
 	"image"
 	"image/draw"
 	"strings"
@@ -25,16 +24,52 @@ func CreateCanvasFromImage(tpl image.Image) (*Canvas, error) {
 	}, nil
 }
 
+// CreateVectorCanvasFromImage creates a canvas which records texts instead of rasterizing them.
+// The recorded texts are written as text elements when the canvas is encoded as SVG.
+func CreateVectorCanvasFromImage(tpl image.Image) (*Canvas, error) {
+	c, err := CreateCanvasFromImage(tpl)
+	if err != nil {
+		return nil, err
+	}
+	c.vector = true
+	return c, nil
+}
+
 type Canvas struct {
 	dst *image.RGBA
 	fdr *font.Drawer
 
-	bgColor    *image.Uniform
-	maxWidth   int
-	lineSpace  int
-	boxPadding config.Padding
-	boxSpace   int
-	boxAlign   box.Align
+	// vector canvas records texts to write them as SVG
+	vector bool
+	texts  []textRecord
+	font   textFont
+
+	bgColor       *image.Uniform
+	fontSize      float64
+	newFace       func(size float64) (font.Face, error)
+	maxWidth      int
+	maxHeight     int
+	maxLines      int
+	minFontSize   float64
+	ellipsis      string
+	lineSpace     int
+	align         box.Align
+	verticalAlign box.VerticalAlign
+	height        int
+	boxPadding    config.Padding
+	boxSpace      int
+	boxAlign      box.Align
+	imgWidth      int
+	imgHeight     int
+	imgFit        box.Fit
+	imgMask       box.Mask
+	imgRadius     int
+	opacity       float64
+}
+
+// Image returns the image which is drawn on this canvas.
+func (c *Canvas) Image() image.Image {
+	return c.dst
 }
 
 // SaveAsPNG saves this canvas as a PNG file into the specified path.
@@ -44,72 +79,87 @@ func (c *Canvas) SaveAsPNG(filename string) error {
 
 // DrawTextAtPoint draws text on this canvas at the specified point.
 func (c *Canvas) DrawTextAtPoint(text string, start config.Point, opts ...textDrawOption) error {
+	// line options are only applied to the text which specifies them
+	c.maxWidth, c.lineSpace = 0, 0
+	c.maxHeight, c.maxLines, c.minFontSize, c.ellipsis = 0, 0, 0, ""
+	c.align, c.verticalAlign, c.height = box.AlignLeft, box.VerticalAlignTop, 0
 	for _, f := range opts {
 		if err := f(c); err != nil {
 			return err
 		}
 	}
 
-	// dot.y points baseline of text
-	c.fdr.Dot.Y = fixed.I(start.Y) + c.fdr.Face.Metrics().Height
-	c.fdr.Dot.X = fixed.I(start.X)
-
 	if c.maxWidth == 0 {
-		c.fdr.DrawString(text)
+		// start.x is the anchor point of the aligned text
+		c.setDot(start)
+		c.fdr.Dot.X -= c.alignOffset(c.fdr.MeasureString(text), 0)
+		c.fdr.Dot.Y += c.verticalAlignOffset(c.measureLines(1))
+		c.drawString(text)
 		return nil
 	}
 
-	c.drawMultiLineText(text)
+	// measure the wrapped text to place it, and then draw it
+	lines, err := c.fitLines(text)
+	if err != nil {
+		return err
+	}
+	c.setDot(start)
+	c.fdr.Dot.Y += c.verticalAlignOffset(c.measureLines(len(lines)))
+	c.drawLines(lines)
 	return nil
 }
 
-func (c *Canvas) drawMultiLineText(text string) {
-	var (
-		x      = c.fdr.Dot.X
-		rtext  = []rune(text)
-		length = len(rtext)
-
-		lbuf bytes.Buffer
-		wbuf bytes.Buffer
-	)
-	for i := 0; i < length; i++ {
-		r := rtext[i]
-
-		wbuf.WriteRune(r)
-
-		switch {
-		case spaceChar(r):
-			// noop
-		case oneByteChar(r) || startBracket(r):
-			if (i + 1) < length {
-				continue
-			}
-		case (i+1) < length && endChar(rtext[i+1]):
-			wbuf.WriteRune(rtext[i+1])
-			i++
-		}
+// measureLines returns the height of the specified number of lines.
+func (c *Canvas) measureLines(n int) fixed.Int26_6 {
+	if n < 1 {
+		return 0
+	}
+	return c.fdr.Face.Metrics().Height*fixed.Int26_6(n) + fixed.I(c.lineSpace*(n-1))
+}
 
-		lbuf.Write(wbuf.Bytes())
+// verticalAlignOffset returns the offset from the top of the box to the top of the text.
+func (c *Canvas) verticalAlignOffset(height fixed.Int26_6) fixed.Int26_6 {
+	if c.height == 0 {
+		return 0
+	}
+	switch c.verticalAlign {
+	case box.VerticalAlignMiddle:
+		return (fixed.I(c.height) - height) / 2
+	case box.VerticalAlignBottom:
+		return fixed.I(c.height) - height
+	default:
+		return 0
+	}
+}
 
-		adv := c.fdr.MeasureBytes(lbuf.Bytes())
-		if adv <= fixed.I(c.maxWidth) {
-			wbuf.Reset()
-			if (i + 1) < length {
-				continue
-			}
-		}
+// setDot moves the dot to draw the first line at the specified point.
+func (c *Canvas) setDot(start config.Point) {
+	// dot.y points baseline of text
+	c.fdr.Dot.Y = fixed.I(start.Y) + c.fdr.Face.Metrics().Height
+	c.fdr.Dot.X = fixed.I(start.X)
+}
 
-		c.fdr.DrawBytes(lbuf.Bytes()[:lbuf.Len()-wbuf.Len()])
-		c.fdr.Dot.X = x
+// drawLines draws each line aligned within the max width.
+func (c *Canvas) drawLines(lines []string) {
+	x := c.fdr.Dot.X
+	for _, l := range lines {
+		l = trimRightSpace(l)
+		c.fdr.Dot.X = x - c.alignOffset(c.fdr.MeasureString(l), fixed.I(c.maxWidth))
+		c.drawString(l)
 		c.fdr.Dot.Y += c.fdr.Face.Metrics().Height + fixed.I(c.lineSpace)
-
-		lbuf.Reset()
-		lbuf.Write(wbuf.Bytes())
-		wbuf.Reset()
 	}
+}
 
-	if len(lbuf.Bytes()) != 0 {
-		c.fdr.DrawBytes(lbuf.Bytes()[:lbuf.Len()-wbuf.Len()])
+// alignOffset returns the offset which is subtracted from the anchor point to get the start of the text.
+// The anchor point is the left edge of the box of the width, or the point of the text if the width is 0.
+func (c *Canvas) alignOffset(adv, width fixed.Int26_6) fixed.Int26_6 {
+	switch c.align {
+	case box.AlignCenter:
+		return (adv - width) / 2
+	case box.AlignRight:
+		return adv - width
+	default:
+		return 0
 	}
 }
 
@@ -121,10 +171,14 @@ func (c *Canvas) DrawBoxTexts(texts []string, start config.Point, opts ...textDr
 	}
 
 	p := image.Pt(start.X, start.Y)
-	if c.boxAlign == box.AlignRight {
+	if c.boxAlign == box.AlignRight || c.boxAlign == box.AlignCenter {
 		n := len(texts)
-		p.X -= c.boxPadding.Left*n + c.boxPadding.Right*n + c.boxSpace*(n-1) +
+		w := c.boxPadding.Left*n + c.boxPadding.Right*n + c.boxSpace*(n-1) +
 			c.fdr.MeasureString(strings.Join(texts, "")).Round()
+		if c.boxAlign == box.AlignCenter {
+			w /= 2
+		}
+		p.X -= w
 	}
 
 	fm := c.fdr.Face.Metrics()
@@ -139,19 +193,34 @@ func (c *Canvas) DrawBoxTexts(texts []string, start config.Point, opts ...textDr
 
 		c.fdr.Dot.X = fixed.I(p.X + c.boxPadding.Left)
 		c.fdr.Dot.Y = fixed.I(p.Y+c.boxPadding.Top-1) + fh
-		c.fdr.DrawString(s)
+		c.drawString(s)
 
 		p.X = rect.Max.X + c.boxSpace
 	}
 	return nil
 }
 
+// DrawRect fills the rectangle on this canvas with the background color.
+func (c *Canvas) DrawRect(start config.Point, width, height int, opts ...textDrawOption) error {
+	for _, f := range opts {
+		if err := f(c); err != nil {
+			return err
+		}
+	}
+	rect := image.Rect(start.X, start.Y, start.X+width, start.Y+height)
+	draw.Draw(c.dst, rect, c.bgColor, image.Point{}, draw.Src)
+	return nil
+}
+
 type textDrawOption func(*Canvas) error
 
 // FontFace sets font face.
+// The font size of this face cannot be changed to fit the text.
 func FontFace(ff font.Face) textDrawOption {
 	return func(c *Canvas) error {
 		c.fdr.Face = ff
+		c.newFace = nil
+		c.font = textFont{}
 		return nil
 	}
 }
@@ -164,6 +233,11 @@ func FontFaceFromFFA(ffa *fontfamily.FontFamily, style fontfamily.Style, size fl
 			return err
 		}
 		c.fdr.Face = ff
+		c.fontSize = size
+		c.font = textFont{family: ffa.FontName(style), style: style}
+		c.newFace = func(size float64) (font.Face, error) {
+			return ffa.NewFace(style, size)
+		}
 		return nil
 	}
 }
@@ -217,6 +291,40 @@ func MaxWidth(max int) textDrawOption {
 	}
 }
 
+// MaxHeight sets maximum height(px) of multi-line text.
+// If the wrapped text exceeds the limit, drawer shrinks the font size or truncates the lines.
+func MaxHeight(px int) textDrawOption {
+	return func(c *Canvas) error {
+		c.maxHeight = px
+		return nil
+	}
+}
+
+// MaxLines sets maximum number of lines of multi-line text.
+func MaxLines(n int) textDrawOption {
+	return func(c *Canvas) error {
+		c.maxLines = n
+		return nil
+	}
+}
+
+// MinFontSize sets minimum font size to fit multi-line text in the box.
+// If the size is zero, drawer does not shrink the font size.
+func MinFontSize(size float64) textDrawOption {
+	return func(c *Canvas) error {
+		c.minFontSize = size
+		return nil
+	}
+}
+
+// Ellipsis sets the string which is added to the end of the truncated text.
+func Ellipsis(s string) textDrawOption {
+	return func(c *Canvas) error {
+		c.ellipsis = s
+		return nil
+	}
+}
+
 // LineSpace sets line space(px) of multi-line text.
 func LineSpacing(px int) textDrawOption {
 	return func(c *Canvas) error {
@@ -225,6 +333,26 @@ func LineSpacing(px int) textDrawOption {
 	}
 }
 
+// Align sets horizontal align of text.
+// Each line of multi-line text is aligned within the max width,
+// otherwise the start point is used as the left, center, or right edge of text.
+func Align(align box.Align) textDrawOption {
+	return func(c *Canvas) error {
+		c.align = align
+		return nil
+	}
+}
+
+// VerticalAlign sets vertical align of text within the box of the specified height(px).
+// The box starts at the start point, and its height is ignored if it is zero.
+func VerticalAlign(align box.VerticalAlign, height int) textDrawOption {
+	return func(c *Canvas) error {
+		c.verticalAlign = align
+		c.height = height
+		return nil
+	}
+}
+
 // BoxPadding sets box padding(px).
 func BoxPadding(bp config.Padding) textDrawOption {
 	return func(c *Canvas) error {
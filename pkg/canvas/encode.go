@@ -0,0 +1,82 @@
+package canvas
+
+import (
+	"fmt"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/HugoSmits86/nativewebp"
+)
+
+type Format string
+
+const (
+	PNG  = Format("png")
+	JPEG = Format("jpeg")
+	WebP = Format("webp")
+	SVG  = Format("svg")
+)
+
+// DefaultJPEGQuality is the default quality of JPEG from 1 to 100.
+const DefaultJPEGQuality = 90
+
+var formatExts = map[string]Format{
+	".png":  PNG,
+	".jpg":  JPEG,
+	".jpeg": JPEG,
+	".webp": WebP,
+	".svg":  SVG,
+}
+
+// ParseFormat parses the format name. "jpg" is accepted as JPEG.
+func ParseFormat(name string) (Format, error) {
+	if f, ok := formatExts["."+strings.ToLower(name)]; ok {
+		return f, nil
+	}
+	return "", fmt.Errorf("unsupported format %q, supported formats are png, jpeg, webp, and svg", name)
+}
+
+// FormatFromExt returns the format which matches the file extension.
+func FormatFromExt(filename string) (Format, bool) {
+	f, ok := formatExts[strings.ToLower(filepath.Ext(filename))]
+	return f, ok
+}
+
+// Ext returns the file extension of the format.
+func (f Format) Ext() string {
+	if f == JPEG {
+		return ".jpg"
+	}
+	return "." + string(f)
+}
+
+// MIMEType returns the media type of the format.
+func (f Format) MIMEType() string {
+	if f == SVG {
+		return "image/svg+xml"
+	}
+	return "image/" + string(f)
+}
+
+// Encode writes this canvas in the format. The quality is only used for JPEG.
+// SVG is only supported by the vector canvas.
+func (c *Canvas) Encode(w io.Writer, format Format, quality int) error {
+	switch format {
+	case PNG:
+		return png.Encode(w, c.dst)
+	case JPEG:
+		if quality == 0 {
+			quality = DefaultJPEGQuality
+		}
+		return jpeg.Encode(w, c.dst, &jpeg.Options{Quality: quality})
+	case WebP:
+		return nativewebp.Encode(w, c.dst, nil)
+	case SVG:
+		return c.EncodeSVG(w)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
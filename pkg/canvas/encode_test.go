@@ -0,0 +1,71 @@
+package canvas
+
+import (
+	"bytes"
+	"image"
+	"strings"
+	"testing"
+
+	_ "golang.org/x/image/webp"
+
+	"github.com/Ladicle/tcardgen/pkg/config"
+)
+
+func TestEncode(t *testing.T) {
+	for _, format := range []Format{PNG, JPEG, WebP} {
+		t.Run(string(format), func(t *testing.T) {
+			c := newTestCanvas(t, 24)
+			var buf bytes.Buffer
+			if err := c.Encode(&buf, format, DefaultJPEGQuality); err != nil {
+				t.Fatalf("failed to encode: %v", err)
+			}
+			img, name, err := image.Decode(&buf)
+			if err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+			if name != string(format) || img.Bounds() != c.dst.Bounds() {
+				t.Fatalf("unexpected image: format=%s, bounds=%v", name, img.Bounds())
+			}
+		})
+	}
+}
+
+func TestEncodeSVG(t *testing.T) {
+	c := newTestCanvas(t, 24)
+	if err := c.Encode(&bytes.Buffer{}, SVG, 0); err == nil {
+		t.Fatal("raster canvas must not be encoded as SVG")
+	}
+
+	c.vector = true
+	if err := c.DrawTextAtPoint("Tom & Jerry", config.Point{X: 10, Y: 20}, FgHexColor("#FF0000")); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := c.Encode(&buf, SVG, 0); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	svg := buf.String()
+	for _, want := range []string{`<image width="1200" height="630"`, `fill="#FF0000"`, `>Tom &amp; Jerry</text>`} {
+		if !strings.Contains(svg, want) {
+			t.Fatalf("SVG does not contain %q:\n%s", want, svg)
+		}
+	}
+}
+
+func TestFormatFromExt(t *testing.T) {
+	testCases := map[string]Format{
+		"out/a.png":  PNG,
+		"out/a.JPG":  JPEG,
+		"out/a.jpeg": JPEG,
+		"out/a.webp": WebP,
+		"out/a.svg":  SVG,
+	}
+	for name, want := range testCases {
+		if got, ok := FormatFromExt(name); !ok || got != want {
+			t.Fatalf("FormatFromExt(%q) returns unexpected format: got=%q, want=%q", name, got, want)
+		}
+	}
+	if _, ok := FormatFromExt("out/"); ok {
+		t.Fatal("directory must not have a format")
+	}
+}
@@ -85,10 +85,27 @@ func (fs *FontFamily) LoadFont(filename string, style Style) error {
 	if f == nil {
 		return errors.New("parsed font is nil")
 	}
-	fs.fonts[style] = f
+	fs.AddFont(style, f)
 	return nil
 }
 
+// AddFont adds the parsed TrueType font as the style.
+func (fs *FontFamily) AddFont(style Style, f *truetype.Font) {
+	fs.fonts[style] = f
+}
+
+// FontName returns the font family name of the style which is defined in the font file.
+func (fs *FontFamily) FontName(style Style) string {
+	f, ok := fs.fonts[style]
+	if !ok {
+		return fs.Name
+	}
+	if name := f.Name(truetype.NameIDFontFamily); name != "" {
+		return name
+	}
+	return fs.Name
+}
+
 // NewFace creates a new font face with size option.
 func (fs *FontFamily) NewFace(style Style, size float64) (font.Face, error) {
 	f, ok := fs.fonts[style]
@@ -0,0 +1,144 @@
+package canvas
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/draw"
+
+	"github.com/Ladicle/tcardgen/pkg/canvas/box"
+	"github.com/Ladicle/tcardgen/pkg/config"
+)
+
+// DrawImage draws the image on this canvas at the specified point.
+// The image is scaled to the image size with the fit mode, and masked with the mask shape and opacity.
+// It returns an error if the image is empty, because its aspect ratio is unknown.
+func (c *Canvas) DrawImage(img image.Image, start config.Point, opts ...textDrawOption) error {
+	c.imgWidth, c.imgHeight, c.imgFit = 0, 0, box.FitStretch
+	c.imgMask, c.imgRadius, c.opacity = box.MaskNone, 0, 1
+	for _, f := range opts {
+		if err := f(c); err != nil {
+			return err
+		}
+	}
+
+	b := img.Bounds()
+	if b.Empty() {
+		return fmt.Errorf("failed to draw the empty image of %dx%d", b.Dx(), b.Dy())
+	}
+	w, h := c.imgWidth, c.imgHeight
+	switch {
+	case w == 0 && h == 0:
+		w, h = b.Dx(), b.Dy()
+	case w == 0:
+		w = b.Dx() * h / b.Dy()
+	case h == 0:
+		h = b.Dy() * w / b.Dx()
+	}
+	rect := image.Rect(start.X, start.Y, start.X+w, start.Y+h)
+
+	dst, src := rect, b
+	switch c.imgFit {
+	case box.FitContain:
+		dst = fitRect(rect, b, math.Min)
+	case box.FitCover:
+		// crop the center of the image which has the same aspect ratio as the box
+		s := fitRect(image.Rect(0, 0, b.Dx(), b.Dy()), rect, math.Min)
+		src = s.Add(b.Min)
+	}
+
+	scaled := image.NewRGBA(rect)
+	draw.CatmullRom.Scale(scaled, dst, img, src, draw.Src, nil)
+
+	mask := &shapeMask{rect: dst, shape: c.imgMask, radius: c.imgRadius, alpha: c.opacity}
+	draw.DrawMask(c.dst, dst, scaled, dst.Min, mask, dst.Min, draw.Over)
+	return nil
+}
+
+// fitRect returns the rectangle which has the same aspect ratio as the size,
+// and is scaled by the factor selected from the width and height ratio, in the center of the box.
+func fitRect(box image.Rectangle, size image.Rectangle, factor func(x, y float64) float64) image.Rectangle {
+	f := factor(float64(box.Dx())/float64(size.Dx()), float64(box.Dy())/float64(size.Dy()))
+	w, h := int(math.Round(float64(size.Dx())*f)), int(math.Round(float64(size.Dy())*f))
+	min := box.Min.Add(image.Pt((box.Dx()-w)/2, (box.Dy()-h)/2))
+	return image.Rect(min.X, min.Y, min.X+w, min.Y+h)
+}
+
+// shapeMask is an alpha mask of the circle or rounded rectangle.
+type shapeMask struct {
+	rect   image.Rectangle
+	shape  box.Mask
+	radius int
+	alpha  float64
+}
+
+func (m *shapeMask) ColorModel() color.Model {
+	return color.AlphaModel
+}
+
+func (m *shapeMask) Bounds() image.Rectangle {
+	return m.rect
+}
+
+func (m *shapeMask) At(x, y int) color.Color {
+	a := m.alpha * m.coverage(float64(x)+0.5, float64(y)+0.5)
+	return color.Alpha{A: uint8(math.Round(255 * math.Max(0, math.Min(1, a))))}
+}
+
+// coverage returns the ratio of the pixel which is covered by the shape.
+func (m *shapeMask) coverage(x, y float64) float64 {
+	var (
+		minX, minY = float64(m.rect.Min.X), float64(m.rect.Min.Y)
+		maxX, maxY = float64(m.rect.Max.X), float64(m.rect.Max.Y)
+	)
+	switch m.shape {
+	case box.MaskCircle:
+		rx, ry := (maxX-minX)/2, (maxY-minY)/2
+		dx, dy := (x-minX-rx)/rx, (y-minY-ry)/ry
+		// distance from the edge of the ellipse in pixels
+		d := (1 - math.Hypot(dx, dy)) * math.Min(rx, ry)
+		return d + 0.5
+	case box.MaskRounded:
+		r := math.Min(float64(m.radius), math.Min(maxX-minX, maxY-minY)/2)
+		cx := math.Max(minX+r, math.Min(x, maxX-r))
+		cy := math.Max(minY+r, math.Min(y, maxY-r))
+		return r - math.Hypot(x-cx, y-cy) + 0.5
+	default:
+		return 1
+	}
+}
+
+// ImageSize sets the size(px) of the drawn image.
+// If either of them is zero, it is calculated from the aspect ratio of the image.
+func ImageSize(width, height int) textDrawOption {
+	return func(c *Canvas) error {
+		c.imgWidth, c.imgHeight = width, height
+		return nil
+	}
+}
+
+// ImageFit sets how to fit the image into the image size.
+func ImageFit(fit box.Fit) textDrawOption {
+	return func(c *Canvas) error {
+		c.imgFit = fit
+		return nil
+	}
+}
+
+// ImageMask sets the shape of the drawn image. The radius is used for the rounded corners.
+func ImageMask(mask box.Mask, radius int) textDrawOption {
+	return func(c *Canvas) error {
+		c.imgMask, c.imgRadius = mask, radius
+		return nil
+	}
+}
+
+// Opacity sets the opacity of the drawn image from 0 to 1.
+func Opacity(opacity float64) textDrawOption {
+	return func(c *Canvas) error {
+		c.opacity = opacity
+		return nil
+	}
+}
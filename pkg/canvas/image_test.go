@@ -0,0 +1,80 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/Ladicle/tcardgen/pkg/canvas/box"
+	"github.com/Ladicle/tcardgen/pkg/config"
+)
+
+func TestFitRect(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		box    image.Rectangle
+		size   image.Rectangle
+		factor func(x, y float64) float64
+		expect image.Rectangle
+	}{
+		{
+			desc:   "Contain wide image in square box",
+			box:    image.Rect(100, 100, 300, 300),
+			size:   image.Rect(0, 0, 400, 200),
+			factor: math.Min,
+			expect: image.Rect(100, 150, 300, 250),
+		},
+		{
+			desc:   "Crop square area from wide image",
+			box:    image.Rect(0, 0, 400, 200),
+			size:   image.Rect(0, 0, 100, 100),
+			factor: math.Min,
+			expect: image.Rect(100, 0, 300, 200),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := fitRect(tc.box, tc.size, tc.factor); got != tc.expect {
+				t.Fatalf("fitRect() returns unexpected value: got=%v, want=%v", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestDrawImageWithMask(t *testing.T) {
+	c, err := CreateCanvasFromImage(image.NewRGBA(image.Rect(0, 0, 100, 100)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := image.NewUniform(color.RGBA{R: 255, A: 255})
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			img.Set(x, y, src.C)
+		}
+	}
+
+	if err := c.DrawImage(img, config.Point{X: 10, Y: 10}, ImageSize(80, 80), ImageMask(box.MaskCircle, 0), Opacity(1)); err != nil {
+		t.Fatal(err)
+	}
+	if a := c.dst.RGBAAt(50, 50).A; a != 255 {
+		t.Fatalf("center of the circle is not drawn: alpha=%d", a)
+	}
+	if a := c.dst.RGBAAt(11, 11).A; a != 0 {
+		t.Fatalf("corner of the circle is drawn: alpha=%d", a)
+	}
+}
+
+func TestDrawEmptyImage(t *testing.T) {
+	c, err := CreateCanvasFromImage(image.NewRGBA(image.Rect(0, 0, 100, 100)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty := image.NewRGBA(image.Rect(0, 0, 10, 0))
+	for _, size := range [][2]int{{0, 0}, {80, 0}, {0, 80}} {
+		if err := c.DrawImage(empty, config.Point{}, ImageSize(size[0], size[1])); err == nil {
+			t.Errorf("%v: want error for the empty image", size)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package canvas
 
 import (
 	"image"
+	_ "image/jpeg"
 	"image/png"
 	"os"
 )
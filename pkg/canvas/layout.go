@@ -0,0 +1,136 @@
+package canvas
+
+import (
+	"bytes"
+	"math"
+	"strings"
+
+	"golang.org/x/image/math/fixed"
+)
+
+// fontSizeStep is the size which is reduced at each step to fit the text.
+const fontSizeStep = 1
+
+// fitLines breaks the text into lines. If the lines exceed the limit of lines,
+// it shrinks the font size step by step until the lines fit, and then truncates the rest lines
+// and adds the ellipsis to the last line.
+func (c *Canvas) fitLines(text string) ([]string, error) {
+	lines := c.breakLines(text)
+	for len(lines) > c.maxLineCount() && c.newFace != nil && c.minFontSize > 0 && c.fontSize > c.minFontSize {
+		c.fontSize = math.Max(c.fontSize-fontSizeStep, c.minFontSize)
+		ff, err := c.newFace(c.fontSize)
+		if err != nil {
+			return nil, err
+		}
+		c.fdr.Face = ff
+		lines = c.breakLines(text)
+	}
+
+	if n := c.maxLineCount(); len(lines) > n {
+		lines = lines[:n]
+		if c.ellipsis != "" {
+			lines[n-1] = c.ellipsize(lines[n-1])
+		}
+	}
+	return lines, nil
+}
+
+// maxLineCount returns the number of lines which can be drawn within the max lines and height.
+func (c *Canvas) maxLineCount() int {
+	n := math.MaxInt
+	if c.maxLines > 0 {
+		n = c.maxLines
+	}
+	if c.maxHeight > 0 {
+		lh := c.fdr.Face.Metrics().Height + fixed.I(c.lineSpace)
+		// the last line does not need the line space
+		if hn := int((fixed.I(c.maxHeight + c.lineSpace)) / lh); hn < n {
+			n = hn
+		}
+	}
+	if n < 1 {
+		// draw at least one line
+		n = 1
+	}
+	return n
+}
+
+// breakLines splits the text into lines which fit within the max width.
+func (c *Canvas) breakLines(text string) []string {
+	var (
+		lines []string
+		line  string
+	)
+	for _, w := range splitWords(text) {
+		// a word which is longer than the max width is placed on its own line
+		if line != "" && c.fdr.MeasureString(line+w) > fixed.I(c.maxWidth) {
+			lines = append(lines, line)
+			line = w
+			continue
+		}
+		line += w
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitWords splits the text at the line break opportunities.
+// Each word contains the trailing space and end characters.
+func splitWords(text string) []string {
+	var (
+		words  []string
+		rtext  = []rune(text)
+		length = len(rtext)
+
+		wbuf bytes.Buffer
+	)
+	for i := 0; i < length; i++ {
+		r := rtext[i]
+
+		wbuf.WriteRune(r)
+
+		switch {
+		case spaceChar(r):
+			// noop
+		case oneByteChar(r) || startBracket(r):
+			if (i + 1) < length {
+				continue
+			}
+		case (i+1) < length && endChar(rtext[i+1]):
+			wbuf.WriteRune(rtext[i+1])
+			i++
+		}
+
+		words = append(words, wbuf.String())
+		wbuf.Reset()
+	}
+	return words
+}
+
+// ellipsize cuts the line at the break opportunity and adds the ellipsis to the end,
+// so that the line fits within the max width.
+func (c *Canvas) ellipsize(line string) string {
+	words := splitWords(line)
+	for n := len(words); n > 0; n-- {
+		s := trimRightSpace(strings.Join(words[:n], "")) + c.ellipsis
+		if c.fdr.MeasureString(s) <= fixed.I(c.maxWidth) {
+			return s
+		}
+	}
+
+	// the first word is too long, so cut it at the character
+	rs := []rune(trimRightSpace(line))
+	for n := len(rs) - 1; n > 0; n-- {
+		s := string(rs[:n]) + c.ellipsis
+		if c.fdr.MeasureString(s) <= fixed.I(c.maxWidth) {
+			return s
+		}
+	}
+	return c.ellipsis
+}
+
+func trimRightSpace(s string) string {
+	return strings.TrimRightFunc(s, spaceChar)
+}
@@ -0,0 +1,201 @@
+package canvas
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/image/math/fixed"
+
+	"github.com/Ladicle/tcardgen/internal/fonttest"
+	"github.com/Ladicle/tcardgen/pkg/canvas/box"
+	"github.com/Ladicle/tcardgen/pkg/canvas/fontfamily"
+)
+
+func newTestCanvas(t *testing.T, size float64, opts ...textDrawOption) *Canvas {
+	t.Helper()
+	c, err := CreateCanvasFromImage(image.NewRGBA(image.Rect(0, 0, 1200, 630)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range append([]textDrawOption{FontFaceFromFFA(fonttest.GoFonts(), fontfamily.Regular, size)}, opts...) {
+		if err := f(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c
+}
+
+func TestBreakLines(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		text   string
+		width  int
+		expect []string
+	}{
+		{
+			desc:   "Text fits in a line",
+			text:   "Hello world",
+			width:  1000,
+			expect: []string{"Hello world"},
+		},
+		{
+			desc:   "Break at spaces",
+			text:   "Hello world foo",
+			width:  80,
+			expect: []string{"Hello ", "world ", "foo"},
+		},
+		{
+			desc:   "Long word is placed on its own line",
+			text:   "Supercalifragilistic word",
+			width:  60,
+			expect: []string{"Supercalifragilistic ", "word"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 24, MaxWidth(tc.width))
+			got := c.breakLines(tc.text)
+			if len(got) != len(tc.expect) {
+				t.Fatalf("breakLines() returns unexpected lines: got=%q, want=%q", got, tc.expect)
+			}
+			for i := range got {
+				if got[i] != tc.expect[i] {
+					t.Fatalf("breakLines() returns unexpected lines: got=%q, want=%q", got, tc.expect)
+				}
+			}
+		})
+	}
+}
+
+func TestFitLines(t *testing.T) {
+	text := "Generate a TwitterCard image for your Hugo posts with a very long title"
+
+	t.Run("Shrink font size to fit max lines", func(t *testing.T) {
+		c := newTestCanvas(t, 72, MaxWidth(946), MaxLines(2), MinFontSize(24))
+		lines, err := c.fitLines(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lines) > 2 {
+			t.Fatalf("lines are not fit: %q", lines)
+		}
+		if c.fontSize >= 72 || c.fontSize < 24 {
+			t.Fatalf("unexpected font size: %v", c.fontSize)
+		}
+	})
+
+	t.Run("Truncate lines at min font size", func(t *testing.T) {
+		c := newTestCanvas(t, 72, MaxWidth(300), MaxHeight(100), MinFontSize(60))
+		lines, err := c.fitLines(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lines) != 1 || c.fontSize != 60 {
+			t.Fatalf("unexpected lines at %v: %q", c.fontSize, lines)
+		}
+	})
+
+	t.Run("Keep font size without limits", func(t *testing.T) {
+		c := newTestCanvas(t, 72, MaxWidth(946))
+		lines, err := c.fitLines(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lines) < 2 || c.fontSize != 72 {
+			t.Fatalf("unexpected lines at %v: %q", c.fontSize, lines)
+		}
+	})
+}
+
+func TestEllipsize(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		text     string
+		maxLines int
+		expect   []string
+	}{
+		{
+			desc:     "Cut at the break opportunity",
+			text:     "Hello world foo bar",
+			maxLines: 1,
+			expect:   []string{"Hello…"},
+		},
+		{
+			desc:     "Keep lines within the limit",
+			text:     "Hello world",
+			maxLines: 2,
+			expect:   []string{"Hello ", "world"},
+		},
+		{
+			desc:     "Keep the long word without the rest lines",
+			text:     "Supercalifragilistic",
+			maxLines: 1,
+			expect:   []string{"Supercalifragilistic"},
+		},
+		{
+			desc:     "Cut the long word which is followed by other words",
+			text:     "Supercalifragilistic word",
+			maxLines: 1,
+			expect:   []string{"Super…"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 24, MaxWidth(100), MaxLines(tc.maxLines), Ellipsis("…"))
+			got, err := c.fitLines(tc.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tc.expect) {
+				t.Fatalf("fitLines() returns unexpected lines: got=%q, want=%q", got, tc.expect)
+			}
+			for i := range got {
+				if got[i] != tc.expect[i] {
+					t.Fatalf("fitLines() returns unexpected lines: got=%q, want=%q", got, tc.expect)
+				}
+				if w := c.fdr.MeasureString(got[i]); len(got) > 1 && w.Ceil() > 100 {
+					t.Fatalf("line %q exceeds the max width: %v", got[i], w.Ceil())
+				}
+			}
+		})
+	}
+}
+
+func TestAlignOffset(t *testing.T) {
+	testCases := []struct {
+		align  box.Align
+		width  int
+		expect int
+	}{
+		{align: box.AlignLeft, width: 100, expect: 0},
+		{align: box.AlignCenter, width: 100, expect: -30},
+		{align: box.AlignRight, width: 100, expect: -60},
+		{align: box.AlignCenter, width: 0, expect: 20},
+		{align: box.AlignRight, width: 0, expect: 40},
+	}
+	for _, tc := range testCases {
+		c := newTestCanvas(t, 24, Align(tc.align))
+		if got := c.alignOffset(fixed.I(40), fixed.I(tc.width)); got != fixed.I(tc.expect) {
+			t.Fatalf("alignOffset(%s, %d) returns unexpected value: got=%v, want=%v", tc.align, tc.width, got, tc.expect)
+		}
+	}
+}
+
+func TestVerticalAlignOffset(t *testing.T) {
+	testCases := []struct {
+		align  box.VerticalAlign
+		height int
+		expect int
+	}{
+		{align: box.VerticalAlignTop, height: 200, expect: 0},
+		{align: box.VerticalAlignMiddle, height: 200, expect: 50},
+		{align: box.VerticalAlignBottom, height: 200, expect: 100},
+		{align: box.VerticalAlignBottom, height: 0, expect: 0},
+	}
+	for _, tc := range testCases {
+		c := newTestCanvas(t, 24, VerticalAlign(tc.align, tc.height))
+		if got := c.verticalAlignOffset(fixed.I(100)); got != fixed.I(tc.expect) {
+			t.Fatalf("verticalAlignOffset(%s, %d) returns unexpected value: got=%v, want=%v", tc.align, tc.height, got, tc.expect)
+		}
+	}
+}
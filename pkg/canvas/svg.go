@@ -0,0 +1,105 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+
+	"github.com/Ladicle/tcardgen/pkg/canvas/fontfamily"
+)
+
+// fontWeights maps the font style to the CSS font weight.
+var fontWeights = map[fontfamily.Style]int{
+	fontfamily.Thin:    100,
+	fontfamily.Light:   300,
+	fontfamily.Regular: 400,
+	fontfamily.Medium:  500,
+	fontfamily.Bold:    700,
+	fontfamily.Black:   900,
+}
+
+type textFont struct {
+	family string
+	style  fontfamily.Style
+}
+
+// textRecord is a text which is drawn on the vector canvas.
+type textRecord struct {
+	text  string
+	x, y  float64
+	font  textFont
+	size  float64
+	color color.Color
+}
+
+// drawString draws the text at the dot and moves the dot to the end of the text.
+// The vector canvas records the text instead of drawing it.
+func (c *Canvas) drawString(s string) {
+	if !c.vector {
+		c.fdr.DrawString(s)
+		return
+	}
+
+	var clr color.Color = color.Black
+	if u, ok := c.fdr.Src.(*image.Uniform); ok {
+		clr = u.C
+	}
+	c.texts = append(c.texts, textRecord{
+		text:  s,
+		x:     float64(c.fdr.Dot.X) / 64,
+		y:     float64(c.fdr.Dot.Y) / 64,
+		font:  c.font,
+		size:  c.fontSize,
+		color: clr,
+	})
+	c.fdr.Dot.X += c.fdr.MeasureString(s)
+}
+
+// EncodeSVG writes this canvas as SVG. The rasterized image is embedded as PNG,
+// and the recorded texts are written as text elements over it.
+func (c *Canvas) EncodeSVG(w io.Writer) error {
+	if !c.vector {
+		return fmt.Errorf("canvas does not record texts, create it as a vector canvas")
+	}
+
+	var img bytes.Buffer
+	if err := png.Encode(&img, c.dst); err != nil {
+		return err
+	}
+
+	b := c.dst.Bounds()
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		b.Dx(), b.Dy(), b.Dx(), b.Dy())
+	fmt.Fprintf(&buf, `<image width="%d" height="%d" href="data:image/png;base64,%s"/>`+"\n",
+		b.Dx(), b.Dy(), base64.StdEncoding.EncodeToString(img.Bytes()))
+	for _, t := range c.texts {
+		r, g, bl, _ := t.color.RGBA()
+		fmt.Fprintf(&buf, `<text x="%.2f" y="%.2f" font-family="%s" font-weight="%d" font-size="%.2fpx" fill="#%02X%02X%02X" xml:space="preserve">`,
+			t.x, t.y, escapeXML(t.font.family), fontWeight(t.font.style), t.size, r>>8, g>>8, bl>>8)
+		buf.WriteString(escapeXML(t.text))
+		buf.WriteString("</text>\n")
+	}
+	buf.WriteString("</svg>\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func fontWeight(style fontfamily.Style) int {
+	if w, ok := fontWeights[style]; ok {
+		return w
+	}
+	return fontWeights[fontfamily.Regular]
+}
+
+func escapeXML(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -1,33 +1,140 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/Ladicle/tcardgen/pkg/canvas/box"
 	"github.com/Ladicle/tcardgen/pkg/canvas/fontfamily"
+	"github.com/Ladicle/tcardgen/pkg/hugo"
 )
 
+// DrawingConfig is a configuration of the card drawing.
+// Elements are drawn in the listed order, so a later element is drawn over the earlier ones.
+// When Elements is empty, the legacy Title, Category, Info and Tags options are converted into it.
 type DrawingConfig struct {
 	Template string               `json:"template,omitempty"`
 	Title    *MultiLineTextOption `json:"title,omitempty"`
 	Category *TextOption          `json:"category,omitempty"`
 	Info     *TextOption          `json:"info,omitempty"`
 	Tags     *BoxTextsOption      `json:"tags,omitempty"`
+	Elements []Element            `json:"elements,omitempty"`
+	// FrontMatter maps each field to the front matter keys.
+	FrontMatter *hugo.FieldKeys `json:"frontMatter,omitempty"`
+	// DescriptionLength is the maximum number of characters of the description which is taken
+	// from the first paragraph of the content.
+	DescriptionLength int `json:"descriptionLength,omitempty"`
+	// ReadingSpeed is the speed of reading which computes the reading time of the content.
+	ReadingSpeed *hugo.ReadingSpeed `json:"readingSpeed,omitempty"`
+	// Languages overlays the configuration for the contents of each language.
+	Languages map[string]*LanguageConfig `json:"languages,omitempty"`
+	// TimeZone is the IANA time zone (e.g. "Asia/Tokyo") which interprets the dates without the
+	// offset and renders the dates. It takes precedence over the timeZone of the Hugo site.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// Location returns the time zone of TimeZone, or nil if it is not set.
+func (c *DrawingConfig) Location() (*time.Location, error) {
+	if c.TimeZone == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeZone %q: %w", c.TimeZone, err)
+	}
+	return loc, nil
+}
+
+// LanguageConfig overlays the drawing configuration for the contents of a language.
+// TimeFormat, DateFormat, Calendar, and Separator replace the options of all text elements,
+// so the default templates of the date and info are built with them.
+type LanguageConfig struct {
+	FontDir    string `json:"fontDir,omitempty"`
+	Template   string `json:"template,omitempty"`
+	TimeFormat string `json:"timeFormat,omitempty"`
+	DateFormat string `json:"dateFormat,omitempty"`
+	Calendar   string `json:"calendar,omitempty"`
+	Separator  string `json:"separator,omitempty"`
 }
 
+type ElementType string
+
+const (
+	ElementText          = ElementType("text")
+	ElementMultiLineText = ElementType("multiLineText")
+	ElementBoxTexts      = ElementType("boxTexts")
+	ElementImage         = ElementType("image")
+	ElementShape         = ElementType("shape")
+)
+
+// DataSource is the name of the post data which is drawn by an element.
+type DataSource string
+
+const (
+	SourceTitle    = DataSource("title")
+	SourceAuthor   = DataSource("author")
+	SourceCategory = DataSource("category")
+	SourceTags     = DataSource("tags")
+	SourceDate     = DataSource("date")
+	SourceInfo     = DataSource("info")
+	// SourceDescription is the description or summary of the front matter, or the first paragraph of the content.
+	SourceDescription = DataSource("description")
+	// SourceReadingTime and SourceWordCount are computed from the content.
+	SourceReadingTime = DataSource("readingTime")
+	SourceWordCount   = DataSource("wordCount")
+)
+
+// Element is a drawing element of the card.
+// Only the option which matches the Type is used.
+type Element struct {
+	Type          ElementType          `json:"type"`
+	Source        DataSource           `json:"source,omitempty"`
+	Text          *TextOption          `json:"text,omitempty"`
+	MultiLineText *MultiLineTextOption `json:"multiLineText,omitempty"`
+	BoxTexts      *BoxTextsOption      `json:"boxTexts,omitempty"`
+	Image         *ImageOption         `json:"image,omitempty"`
+	Shape         *ShapeOption         `json:"shape,omitempty"`
+}
+
+// TextOption is an option of the text drawing.
+// Template is a Go text/template which renders the drawn text from the post data.
+// When it is empty, the default template of the element source is used.
+// Align is the horizontal align, and Start is used as the left, center, or right edge of the text.
+// VerticalAlign aligns the text within the box of Height from Start.
+// The default date is formatted with the Go layout of TimeFormat, or the CLDR pattern or style
+// (short, medium, long, or full) of DateFormat in the Calendar if it is set.
+// ShowUpdated shows the last modified date as "Updated <date>" when it is later than the date.
 type TextOption struct {
-	Start      *Point           `json:"start,omitempty"`
-	FgHexColor string           `json:"fgHexColor,omitempty"`
-	FontSize   float64          `json:"fontSize,omitempty"`
-	FontStyle  fontfamily.Style `json:"fontStyle,omitempty"`
-	Separator  string           `json:"separator,omitempty"`
-	TimeFormat string           `json:"timeFormat,omitempty"`
-	Enabled    *bool            `json:"enabled,omitempty"`
+	Template      string            `json:"template,omitempty"`
+	Start         *Point            `json:"start,omitempty"`
+	FgHexColor    string            `json:"fgHexColor,omitempty"`
+	FontSize      float64           `json:"fontSize,omitempty"`
+	FontStyle     fontfamily.Style  `json:"fontStyle,omitempty"`
+	Separator     string            `json:"separator,omitempty"`
+	TimeFormat    string            `json:"timeFormat,omitempty"`
+	DateFormat    string            `json:"dateFormat,omitempty"`
+	Calendar      string            `json:"calendar,omitempty"`
+	ShowUpdated   *bool             `json:"showUpdated,omitempty"`
+	Align         box.Align         `json:"align,omitempty"`
+	VerticalAlign box.VerticalAlign `json:"verticalAlign,omitempty"`
+	Height        int               `json:"height,omitempty"`
+	Enabled       *bool             `json:"enabled,omitempty"`
 }
 
+// MultiLineTextOption is an option of the text which is wrapped at MaxWidth.
+// Each line is aligned within MaxWidth from Start.
+// If Height is not set, MaxHeight is used as the height of the box to align the text vertically.
+// When the wrapped text exceeds MaxHeight or MaxLines, the font size is reduced down to MinFontSize,
+// and then the lines which still do not fit are truncated with Ellipsis.
 type MultiLineTextOption struct {
 	TextOption
-	MaxWidth    int   `json:"maxWidth,omitempty"`
-	LineSpacing *int  `json:"lineSpacing,omitempty"`
-	Enabled     *bool `json:"enabled,omitempty"`
+	MaxWidth    int     `json:"maxWidth,omitempty"`
+	MaxHeight   int     `json:"maxHeight,omitempty"`
+	MaxLines    int     `json:"maxLines,omitempty"`
+	MinFontSize float64 `json:"minFontSize,omitempty"`
+	Ellipsis    *string `json:"ellipsis,omitempty"`
+	LineSpacing *int    `json:"lineSpacing,omitempty"`
+	Enabled     *bool   `json:"enabled,omitempty"`
 }
 
 type BoxTextsOption struct {
@@ -41,6 +148,33 @@ type BoxTextsOption struct {
 	TitleCaseEnabled *bool     `json:"titleCaseEnabled,omitempty"`
 }
 
+// ImageOption is an option of the image drawing.
+// The image is loaded from the path in the front matter Field, or from Path if the field is empty.
+// A path in the front matter is relative to Dir, or the directory of the content file if Dir is empty.
+// The path which starts with "/" without Dir is the path from the site root.
+// The image is scaled into the box of Width and Height with Fit, and masked with Mask and Opacity.
+type ImageOption struct {
+	Start   *Point   `json:"start,omitempty"`
+	Path    string   `json:"path,omitempty"`
+	Field   string   `json:"field,omitempty"`
+	Dir     string   `json:"dir,omitempty"`
+	Width   int      `json:"width,omitempty"`
+	Height  int      `json:"height,omitempty"`
+	Fit     box.Fit  `json:"fit,omitempty"`
+	Opacity *float64 `json:"opacity,omitempty"`
+	Mask    box.Mask `json:"mask,omitempty"`
+	Radius  int      `json:"radius,omitempty"`
+	Enabled *bool    `json:"enabled,omitempty"`
+}
+
+type ShapeOption struct {
+	Start      *Point `json:"start,omitempty"`
+	Width      int    `json:"width,omitempty"`
+	Height     int    `json:"height,omitempty"`
+	BgHexColor string `json:"bgHexColor,omitempty"`
+	Enabled    *bool  `json:"enabled,omitempty"`
+}
+
 type Point struct {
 	X int `json:"px"`
 	Y int `json:"py"`
@@ -52,3 +186,8 @@ type Padding struct {
 	Bottom int `json:"bottom"`
 	Left   int `json:"left"`
 }
+
+// IsEnabled returns false only when the flag is explicitly disabled.
+func IsEnabled(b *bool) bool {
+	return b == nil || *b
+}
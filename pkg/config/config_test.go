@@ -0,0 +1,17 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestLocation(t *testing.T) {
+	if loc, err := (&DrawingConfig{}).Location(); loc != nil || err != nil {
+		t.Errorf("want no location, but got %v, %v", loc, err)
+	}
+	if loc, err := (&DrawingConfig{TimeZone: "Asia/Tokyo"}).Location(); err != nil || loc.String() != "Asia/Tokyo" {
+		t.Errorf("unexpected location: %v, %v", loc, err)
+	}
+	if _, err := (&DrawingConfig{TimeZone: "Nowhere/City"}).Location(); err == nil {
+		t.Error("want error for invalid time zone")
+	}
+}
@@ -1,11 +1,19 @@
 package config
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/Ladicle/tcardgen/pkg/canvas/box"
 	"github.com/Ladicle/tcardgen/pkg/canvas/fontfamily"
+	"github.com/Ladicle/tcardgen/pkg/hugo"
 )
 
-const DefaultTemplate = "example/template.png"
+const (
+	DefaultTemplate = "example/template.png"
+
+	defaultShapeHexColor = "#000000"
+)
 
 var defaultCnf = DrawingConfig{
 	Title: &MultiLineTextOption{
@@ -17,6 +25,7 @@ var defaultCnf = DrawingConfig{
 		},
 		MaxWidth:    946,
 		LineSpacing: ptrInt(10),
+		Ellipsis:    ptrString("…"),
 	},
 	Category: &TextOption{
 		Enabled:    ptrBool(true),
@@ -51,6 +60,17 @@ var defaultCnf = DrawingConfig{
 	},
 }
 
+// defaultDescription is the default option of the description which is drawn under the title.
+var defaultDescription = &MultiLineTextOption{
+	TextOption: TextOption{
+		Start:      &Point{X: 126, Y: 290},
+		FgHexColor: "#8D8D8D",
+		FontSize:   32,
+		FontStyle:  fontfamily.Regular,
+	},
+	MaxLines: 2,
+}
+
 func Defaulting(cnf *DrawingConfig, tplImg string) {
 	if tplImg != "" {
 		cnf.Template = tplImg
@@ -77,16 +97,171 @@ func Defaulting(cnf *DrawingConfig, tplImg string) {
 		cnf.Tags = &BoxTextsOption{}
 	}
 	defaultTags(cnf.Tags)
+
+	if cnf.FrontMatter == nil {
+		cnf.FrontMatter = &hugo.FieldKeys{}
+	}
+	cnf.FrontMatter.Defaulting()
+
+	if cnf.DescriptionLength <= 0 {
+		cnf.DescriptionLength = hugo.DefaultDescriptionLength
+	}
+
+	if cnf.ReadingSpeed == nil {
+		cnf.ReadingSpeed = &hugo.ReadingSpeed{}
+	}
+	cnf.ReadingSpeed.Defaulting()
+
+	if len(cnf.Elements) == 0 {
+		cnf.Elements = legacyElements(cnf)
+	}
+	for i := range cnf.Elements {
+		defaultingElement(&cnf.Elements[i])
+	}
+}
+
+// legacyElements converts the Title, Category, Info and Tags options into elements.
+func legacyElements(cnf *DrawingConfig) []Element {
+	return []Element{
+		{Type: ElementMultiLineText, Source: SourceTitle, MultiLineText: cnf.Title},
+		{Type: ElementText, Source: SourceCategory, Text: cnf.Category},
+		{Type: ElementText, Source: SourceInfo, Text: cnf.Info},
+		{Type: ElementBoxTexts, Source: SourceTags, BoxTexts: cnf.Tags},
+	}
+}
+
+func defaultingElement(e *Element) {
+	switch e.Type {
+	case ElementText:
+		if e.Text == nil {
+			e.Text = &TextOption{}
+		}
+		setArgsAsDefaultTextOption(e.Text, defaultTextOption(e.Source))
+		defaultingTemplate(e.Text, e.Source)
+	case ElementMultiLineText:
+		if e.MultiLineText == nil {
+			e.MultiLineText = &MultiLineTextOption{}
+		}
+		setArgsAsDefaultTextOption(&e.MultiLineText.TextOption, defaultTextOption(e.Source))
+		if e.Source == SourceDescription && e.MultiLineText.MaxLines == 0 {
+			e.MultiLineText.MaxLines = defaultDescription.MaxLines
+		}
+		defaultingMultiLineText(e.MultiLineText)
+		defaultingTemplate(&e.MultiLineText.TextOption, e.Source)
+	case ElementBoxTexts:
+		if e.Source == "" {
+			e.Source = SourceTags
+		}
+		if e.BoxTexts == nil {
+			e.BoxTexts = &BoxTextsOption{}
+		}
+		defaultTags(e.BoxTexts)
+	case ElementImage:
+		if e.Image == nil {
+			e.Image = &ImageOption{}
+		}
+		defaultingImage(e.Image)
+	case ElementShape:
+		if e.Shape == nil {
+			e.Shape = &ShapeOption{}
+		}
+		if e.Shape.Start == nil {
+			e.Shape.Start = &Point{}
+		}
+		if e.Shape.BgHexColor == "" {
+			e.Shape.BgHexColor = defaultShapeHexColor
+		}
+	}
+}
+
+func defaultingImage(imo *ImageOption) {
+	if imo.Start == nil {
+		imo.Start = &Point{}
+	}
+	if imo.Fit == "" {
+		imo.Fit = box.FitCover
+	}
+	if imo.Mask == "" {
+		imo.Mask = box.MaskNone
+	}
+	if imo.Opacity == nil {
+		imo.Opacity = ptrFloat64(1)
+	}
+}
+
+// defaultTextOption returns the default text option of the legacy slot which draws the source.
+func defaultTextOption(src DataSource) *TextOption {
+	switch src {
+	case SourceTitle:
+		return &defaultCnf.Title.TextOption
+	case SourceCategory:
+		return defaultCnf.Category
+	case SourceTags:
+		return &defaultCnf.Tags.TextOption
+	case SourceDescription:
+		return &defaultDescription.TextOption
+	default:
+		return defaultCnf.Info
+	}
+}
+
+// defaultingTemplate sets the template which renders the source as same as the legacy slot.
+func defaultingTemplate(to *TextOption, src DataSource) {
+	if to.Template != "" {
+		return
+	}
+	switch src {
+	case SourceTitle:
+		to.Template = "{{ .Title }}"
+	case SourceAuthor:
+		to.Template = "{{ .Author }}"
+	case SourceDescription:
+		to.Template = "{{ .Description }}"
+	case SourceCategory:
+		to.Template = "{{ .Category | upper }}"
+	case SourceTags:
+		to.Template = fmt.Sprintf("{{ .Tags | join %s }}", strconv.Quote(to.Separator))
+	case SourceDate:
+		to.Template = dateTemplate(to)
+	case SourceInfo:
+		to.Template = fmt.Sprintf("{{ .Author }}{{ %s }}%s", strconv.Quote(to.Separator), dateTemplate(to))
+	case SourceReadingTime:
+		to.Template = "{{ .ReadingTime }} min read"
+	case SourceWordCount:
+		to.Template = "{{ .WordCount }} words"
+	}
+}
+
+// dateTemplate returns the template which renders the date, or the updated date if ShowUpdated is set.
+func dateTemplate(to *TextOption) string {
+	format := fmt.Sprintf("date %s", strconv.Quote(to.TimeFormat))
+	if to.DateFormat != "" {
+		format = fmt.Sprintf("cldate %s", strconv.Quote(to.DateFormat))
+		if to.Calendar != "" {
+			format += " " + strconv.Quote(to.Calendar)
+		}
+	}
+	if to.ShowUpdated != nil && *to.ShowUpdated {
+		return fmt.Sprintf("{{ if .Updated }}{{ .Lastmod | %s | updated }}{{ else }}{{ .Date | %s }}{{ end }}", format, format)
+	}
+	return fmt.Sprintf("{{ .Date | %s }}", format)
 }
 
 func defaultingTitle(mto *MultiLineTextOption) {
 	setArgsAsDefaultTextOption(&mto.TextOption, &defaultCnf.Title.TextOption)
+	defaultingMultiLineText(mto)
+}
+
+func defaultingMultiLineText(mto *MultiLineTextOption) {
 	if mto.MaxWidth == 0 {
 		mto.MaxWidth = defaultCnf.Title.MaxWidth
 	}
 	if mto.LineSpacing == nil {
 		mto.LineSpacing = defaultCnf.Title.LineSpacing
 	}
+	if mto.Ellipsis == nil {
+		mto.Ellipsis = defaultCnf.Title.Ellipsis
+	}
 }
 
 func defaultingCategory(to *TextOption) {
@@ -158,3 +333,11 @@ func ptrInt(x int) *int {
 func ptrBool(b bool) *bool {
 	return &b
 }
+
+func ptrString(s string) *string {
+	return &s
+}
+
+func ptrFloat64(f float64) *float64 {
+	return &f
+}
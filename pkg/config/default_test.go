@@ -0,0 +1,131 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/Ladicle/tcardgen/pkg/hugo"
+)
+
+func TestDefaultingElements(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		cnf        *DrawingConfig
+		expectType []ElementType
+		expectSrc  []DataSource
+	}{
+		{
+			desc:       "Legacy options are converted into elements",
+			cnf:        &DrawingConfig{},
+			expectType: []ElementType{ElementMultiLineText, ElementText, ElementText, ElementBoxTexts},
+			expectSrc:  []DataSource{SourceTitle, SourceCategory, SourceInfo, SourceTags},
+		},
+		{
+			desc: "Elements take precedence over legacy options",
+			cnf: &DrawingConfig{
+				Title: &MultiLineTextOption{MaxWidth: 100},
+				Elements: []Element{
+					{Type: ElementText, Source: SourceAuthor},
+					{Type: ElementBoxTexts},
+				},
+			},
+			expectType: []ElementType{ElementText, ElementBoxTexts},
+			expectSrc:  []DataSource{SourceAuthor, SourceTags},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			Defaulting(tc.cnf, "")
+			if len(tc.cnf.Elements) != len(tc.expectType) {
+				t.Fatalf("unexpected number of elements: got=%d, want=%d", len(tc.cnf.Elements), len(tc.expectType))
+			}
+			for i, e := range tc.cnf.Elements {
+				if e.Type != tc.expectType[i] || e.Source != tc.expectSrc[i] {
+					t.Fatalf("unexpected element[%d]: got=%s/%s, want=%s/%s",
+						i, e.Type, e.Source, tc.expectType[i], tc.expectSrc[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultingElementStyle(t *testing.T) {
+	cnf := &DrawingConfig{
+		Elements: []Element{
+			{Type: ElementMultiLineText, Source: SourceTitle},
+			{Type: ElementText, Source: SourceCategory, Text: &TextOption{FgHexColor: "#FFFFFF"}},
+			{Type: ElementMultiLineText, Source: SourceDescription, MultiLineText: &MultiLineTextOption{MaxWidth: 600}},
+		},
+	}
+	Defaulting(cnf, "")
+
+	title := cnf.Elements[0].MultiLineText
+	if title == nil || title.MaxWidth != defaultCnf.Title.MaxWidth || title.FontSize != defaultCnf.Title.FontSize {
+		t.Fatalf("title element is not defaulted: %#+v", title)
+	}
+	category := cnf.Elements[1].Text
+	if category.FgHexColor != "#FFFFFF" || category.FontSize != defaultCnf.Category.FontSize {
+		t.Fatalf("category element is not defaulted: %#+v", category)
+	}
+	desc := cnf.Elements[2].MultiLineText
+	if desc.MaxWidth != 600 || desc.MaxLines != defaultDescription.MaxLines ||
+		desc.FontSize != defaultDescription.FontSize || desc.Template != "{{ .Description }}" {
+		t.Fatalf("description element is not defaulted: %#+v", desc)
+	}
+	if cnf.DescriptionLength != hugo.DefaultDescriptionLength {
+		t.Fatalf("description length is not defaulted: %d", cnf.DescriptionLength)
+	}
+}
+
+func TestDefaultingTemplate(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		src    DataSource
+		to     *TextOption
+		expect string
+	}{
+		{
+			desc:   "Category is upper case",
+			src:    SourceCategory,
+			to:     &TextOption{},
+			expect: "{{ .Category | upper }}",
+		},
+		{
+			desc:   "Info joins author and date with separator",
+			src:    SourceInfo,
+			to:     &TextOption{Separator: `"・"`, TimeFormat: "Jan 2"},
+			expect: `{{ .Author }}{{ "\"・\"" }}{{ .Date | date "Jan 2" }}`,
+		},
+		{
+			desc:   "Date is formatted with CLDR pattern in calendar",
+			src:    SourceDate,
+			to:     &TextOption{TimeFormat: "Jan 2", DateFormat: "long", Calendar: "japanese"},
+			expect: `{{ .Date | cldate "long" "japanese" }}`,
+		},
+		{
+			desc:   "Info shows updated date",
+			src:    SourceInfo,
+			to:     &TextOption{Separator: " / ", TimeFormat: "Jan 2", ShowUpdated: ptrBool(true)},
+			expect: `{{ .Author }}{{ " / " }}{{ if .Updated }}{{ .Lastmod | date "Jan 2" | updated }}{{ else }}{{ .Date | date "Jan 2" }}{{ end }}`,
+		},
+		{
+			desc:   "Reading time",
+			src:    SourceReadingTime,
+			to:     &TextOption{},
+			expect: "{{ .ReadingTime }} min read",
+		},
+		{
+			desc:   "Template is not overwritten",
+			src:    SourceTitle,
+			to:     &TextOption{Template: "{{ .Title | upper }}"},
+			expect: "{{ .Title | upper }}",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			defaultingTemplate(tc.to, tc.src)
+			if tc.to.Template != tc.expect {
+				t.Fatalf("unexpected template: got=%q, want=%q", tc.to.Template, tc.expect)
+			}
+		})
+	}
+}
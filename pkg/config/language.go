@@ -0,0 +1,69 @@
+package config
+
+import (
+	"encoding/json"
+)
+
+// ForLanguage returns a copy of the configuration which is overlaid by the language configuration.
+// It must be called before Defaulting, because the default templates are built from the date formats
+// and separator.
+func (c *DrawingConfig) ForLanguage(lc *LanguageConfig) (*DrawingConfig, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	cnf := &DrawingConfig{}
+	if err := json.Unmarshal(b, cnf); err != nil {
+		return nil, err
+	}
+	cnf.Languages = nil
+	if lc.Template != "" {
+		cnf.Template = lc.Template
+	}
+
+	// the legacy options are created to be converted into the elements
+	if cnf.Category == nil {
+		cnf.Category = &TextOption{}
+	}
+	if cnf.Info == nil {
+		cnf.Info = &TextOption{}
+	}
+	overlayText(cnf.Category, lc)
+	overlayText(cnf.Info, lc)
+	for i := range cnf.Elements {
+		e := &cnf.Elements[i]
+		switch e.Type {
+		case ElementText:
+			if e.Text == nil {
+				e.Text = &TextOption{}
+			}
+			overlayText(e.Text, lc)
+		case ElementMultiLineText:
+			if e.MultiLineText == nil {
+				e.MultiLineText = &MultiLineTextOption{}
+			}
+			overlayText(&e.MultiLineText.TextOption, lc)
+		case ElementBoxTexts:
+			if e.BoxTexts == nil {
+				e.BoxTexts = &BoxTextsOption{}
+			}
+			overlayText(&e.BoxTexts.TextOption, lc)
+		}
+	}
+	return cnf, nil
+}
+
+func overlayText(to *TextOption, lc *LanguageConfig) {
+	if lc.TimeFormat != "" {
+		to.TimeFormat = lc.TimeFormat
+	}
+	if lc.DateFormat != "" {
+		to.DateFormat = lc.DateFormat
+	}
+	if lc.Calendar != "" {
+		to.Calendar = lc.Calendar
+	}
+	if lc.Separator != "" {
+		to.Separator = lc.Separator
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestForLanguage(t *testing.T) {
+	cnf := &DrawingConfig{
+		Template: "template.png",
+		Elements: []Element{
+			{Type: ElementText, Source: SourceDate},
+			{Type: ElementText, Source: SourceDate, Text: &TextOption{TimeFormat: "Jan 2"}},
+			{Type: ElementMultiLineText, Source: SourceTitle},
+		},
+		Languages: map[string]*LanguageConfig{
+			"ja": {Template: "template-ja.png", TimeFormat: "1月2日", Separator: " / "},
+			"de": {DateFormat: "long"},
+		},
+	}
+	lcnf, err := cnf.ForLanguage(cnf.Languages["ja"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	dcnf, err := cnf.ForLanguage(cnf.Languages["de"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	Defaulting(lcnf, "")
+	Defaulting(dcnf, "")
+	Defaulting(cnf, "")
+
+	if lcnf.Template != "template-ja.png" || lcnf.Languages != nil {
+		t.Errorf("unexpected template %q or languages %v", lcnf.Template, lcnf.Languages)
+	}
+	for i, expect := range []string{`{{ .Date | date "Jan 2" }}`, `{{ .Date | date "Jan 2" }}`} {
+		if got := cnf.Elements[i].Text.Template; got != expect {
+			t.Errorf("original element %d: want %q, but got %q", i, expect, got)
+		}
+	}
+	for i, expect := range []string{`{{ .Date | date "1月2日" }}`, `{{ .Date | date "1月2日" }}`} {
+		if got := lcnf.Elements[i].Text.Template; got != expect {
+			t.Errorf("language element %d: want %q, but got %q", i, expect, got)
+		}
+	}
+
+	if got, expect := dcnf.Elements[1].Text.Template, `{{ .Date | cldate "long" }}`; got != expect {
+		t.Errorf("language date format: want %q, but got %q", expect, got)
+	}
+
+	legacy, err := (&DrawingConfig{}).ForLanguage(&LanguageConfig{Separator: " / "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Defaulting(legacy, "")
+	if got, expect := legacy.Elements[2].Text.Template, `{{ .Author }}{{ " / " }}{{ .Date | date "Jan 2" }}`; got != expect {
+		t.Errorf("legacy info: want %q, but got %q", expect, got)
+	}
+}
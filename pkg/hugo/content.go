@@ -0,0 +1,153 @@
+package hugo
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const fmReadingTime = "readingTime"
+
+// DefaultDescriptionLength is the maximum number of characters of the description from the content.
+const DefaultDescriptionLength = 140
+
+// ReadingSpeed is the speed of reading which computes the reading time of the content.
+type ReadingSpeed struct {
+	// WordsPerMinute is the number of the words which are separated by spaces read in a minute.
+	WordsPerMinute int `json:"wordsPerMinute,omitempty"`
+	// CJKCharsPerMinute is the number of the Chinese and Japanese characters read in a minute.
+	CJKCharsPerMinute int `json:"cjkCharsPerMinute,omitempty"`
+}
+
+// DefaultReadingSpeed is the reading speed which Hugo uses.
+var DefaultReadingSpeed = ReadingSpeed{
+	WordsPerMinute:    213,
+	CJKCharsPerMinute: 501,
+}
+
+// Defaulting sets the default speed to the empty fields.
+func (s *ReadingSpeed) Defaulting() {
+	if s.WordsPerMinute <= 0 {
+		s.WordsPerMinute = DefaultReadingSpeed.WordsPerMinute
+	}
+	if s.CJKCharsPerMinute <= 0 {
+		s.CJKCharsPerMinute = DefaultReadingSpeed.CJKCharsPerMinute
+	}
+}
+
+// readingTime returns the minutes to read the words and CJK characters, which is rounded up.
+func (s ReadingSpeed) readingTime(words, cjk int) int {
+	minutes := float64(words)/float64(s.WordsPerMinute) + float64(cjk)/float64(s.CJKCharsPerMinute)
+	return int(math.Ceil(minutes))
+}
+
+var (
+	htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+	shortcodePattern   = regexp.MustCompile(`(?s)\{\{[<%].*?[%>]\}\}`)
+	htmlTagPattern     = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	imagePattern       = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	linkPattern        = regexp.MustCompile(`\[([^\]]*)\](\([^)]*\)|\[[^\]]*\])`)
+	linkDefPattern     = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s`)
+	blockPrefixPattern = regexp.MustCompile(`^\s*(#{1,6}\s+|>\s?|[-*+]\s+|\d+[.)]\s+)`)
+	rulePattern        = regexp.MustCompile(`^([-*_=]\s*){3,}$`)
+	emphasisReplacer   = strings.NewReplacer("**", "", "__", "", "~~", "", "*", "", "`", "")
+)
+
+// plainText returns the text of the Markdown content without the syntax, images, comments,
+// shortcodes, and HTML tags. The code blocks are kept as the text.
+func plainText(content []byte) string {
+	s := htmlCommentPattern.ReplaceAllString(string(content), "")
+	s = shortcodePattern.ReplaceAllString(s, "")
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = plainLine(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// firstParagraph returns the plain text of the first paragraph of the Markdown content. Headings,
+// code blocks, tables, rules, and the lines which have no text such as images are skipped.
+func firstParagraph(content []byte) string {
+	s := htmlCommentPattern.ReplaceAllString(string(content), "")
+	s = shortcodePattern.ReplaceAllString(s, "")
+
+	var (
+		para    []string
+		inFence bool
+	)
+	for _, line := range strings.Split(s, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "|") {
+			continue
+		}
+		if rulePattern.MatchString(trimmed) {
+			if strings.Trim(trimmed, "=-") == "" {
+				// the lines underlined with "=" or "-" are the heading
+				para = nil
+			}
+			continue
+		}
+		text := plainLine(line)
+		if text == "" {
+			if len(para) != 0 && trimmed == "" {
+				break
+			}
+			continue
+		}
+		para = append(para, text)
+	}
+	return strings.Join(strings.Fields(strings.Join(para, " ")), " ")
+}
+
+// truncate cuts the text to n characters and adds "…" to the end if it is cut.
+func truncate(s string, n int) string {
+	rs := []rune(s)
+	if len(rs) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(rs[:n])) + "…"
+}
+
+// plainLine returns the text of the Markdown line without the syntax.
+func plainLine(line string) string {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") || linkDefPattern.MatchString(line) {
+		return ""
+	}
+	line = blockPrefixPattern.ReplaceAllString(line, "")
+	line = imagePattern.ReplaceAllString(line, "")
+	line = linkPattern.ReplaceAllString(line, "$1")
+	line = htmlTagPattern.ReplaceAllString(line, "")
+	return strings.TrimSpace(emphasisReplacer.Replace(line))
+}
+
+// countWords returns the number of the words which are separated by spaces and the number of the
+// Chinese and Japanese characters, which are counted one by one because they are not separated.
+// Korean is separated by spaces, so it is counted as words.
+func countWords(text string) (words, cjk int) {
+	inWord := false
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			cjk++
+			inWord = false
+		case unicode.IsSpace(r):
+			inWord = false
+		case !inWord && (unicode.IsLetter(r) || unicode.IsNumber(r)):
+			// a token without letters and numbers such as "-" is not a word
+			words++
+			inWord = true
+		}
+	}
+	return words, cjk
+}
+
+func isCJK(r rune) bool {
+	// the prolonged sound mark "ー" is not Katakana but used in the Katakana words
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+}
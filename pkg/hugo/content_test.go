@@ -0,0 +1,211 @@
+package hugo
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlainText(t *testing.T) {
+	content := `## Getting *Started*
+
+<!-- comment -->
+Read the [docs](https://example.com) and ![logo](logo.png) **now**.
+{{< tweet user="Ladicle" id="1" >}}
+
+- item <br/> one
+> quoted ` + "`code`" + `
+
+` + "```go" + `
+fmt.Println("hi")
+` + "```" + `
+[docs]: https://example.com
+`
+	expect := `Getting Started
+
+
+Read the docs and  now.
+
+
+item  one
+quoted code
+
+
+fmt.Println("hi")
+
+
+`
+	if got := plainText([]byte(content)); got != expect {
+		t.Errorf("want %q, but got %q", expect, got)
+	}
+}
+
+func TestCountWords(t *testing.T) {
+	testCases := []struct {
+		text  string
+		words int
+		cjk   int
+	}{
+		{text: "Hello, world! It's a - test.", words: 5},
+		{text: "HugoでTwitterCardを生成したい", words: 2, cjk: 7},
+		{text: "カード・イメージ", cjk: 7},
+		{text: "안녕하세요 세계", words: 2},
+		{text: "", words: 0},
+	}
+	for _, tc := range testCases {
+		words, cjk := countWords(tc.text)
+		if words != tc.words || cjk != tc.cjk {
+			t.Errorf("%q: want %d words and %d characters, but got %d and %d", tc.text, tc.words, tc.cjk, words, cjk)
+		}
+	}
+}
+
+func TestParseFrontMatterReadingTime(t *testing.T) {
+	header := "---\ntitle: Title\nauthor: Ladicle\ncategories: [go]\ntags: [go]\ndate: 2020-06-21\n"
+	body := strings.Repeat("word ", 300) + strings.Repeat("字", 600)
+
+	testCases := []struct {
+		desc          string
+		input         string
+		opts          []ParseOption
+		expectWords   int
+		expectReading int
+	}{
+		{
+			desc:          "Computed with the default speed",
+			input:         header + "---\n" + body,
+			expectWords:   900,
+			expectReading: 3,
+		},
+		{
+			desc:          "Computed with the configured speed",
+			input:         header + "---\n" + body,
+			opts:          []ParseOption{WithReadingSpeed(ReadingSpeed{WordsPerMinute: 100})},
+			expectWords:   900,
+			expectReading: 5,
+		},
+		{
+			desc:          "Overridden by the front matter",
+			input:         header + "readingTime: 8\n---\n" + body,
+			expectWords:   900,
+			expectReading: 8,
+		},
+		{
+			desc:  "Empty content",
+			input: header + "---\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fm, err := parseFrontMatter(io.Discard, strings.NewReader(tc.input), time.Now(), tc.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fm.WordCount != tc.expectWords || fm.ReadingTime != tc.expectReading {
+				t.Errorf("want %d words and %d minutes, but got %d and %d",
+					tc.expectWords, tc.expectReading, fm.WordCount, fm.ReadingTime)
+			}
+		})
+	}
+
+	input := header + "readingTime: soon\n---\n"
+	if _, err := parseFrontMatter(io.Discard, strings.NewReader(input), time.Now()); err == nil {
+		t.Error("want error for invalid readingTime")
+	}
+}
+
+func TestFirstParagraph(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		content string
+		expect  string
+	}{
+		{
+			desc: "Skip headings, images, and code blocks",
+			content: `# Title
+
+![cover](cover.png)
+
+` + "```sh\n$ tcardgen\n\nout\n```" + `
+This is the **first** paragraph
+with [a link](https://example.com).
+
+Second paragraph.
+`,
+			expect: "This is the first paragraph with a link.",
+		},
+		{
+			desc: "Skip setext headings and rules",
+			content: `Title
+=====
+---
+<!--more-->
+{{< figure src="a.png" >}}
+Body text.`,
+			expect: "Body text.",
+		},
+		{
+			desc:    "No paragraph",
+			content: "## Heading\n\n| a | b |\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := firstParagraph([]byte(tc.content)); got != tc.expect {
+				t.Errorf("want %q, but got %q", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestParseFrontMatterDescription(t *testing.T) {
+	header := "---\ntitle: Title\nauthor: Ladicle\ncategories: [go]\ntags: [go]\ndate: 2020-06-21\n"
+	body := "Generate a TwitterCard image for your Hugo posts.\n\nMore text."
+
+	testCases := []struct {
+		desc   string
+		input  string
+		opts   []ParseOption
+		expect string
+	}{
+		{
+			desc:   "Description of the front matter",
+			input:  header + "description: Desc\nsummary: Summary\n---\n" + body,
+			expect: "Desc",
+		},
+		{
+			desc:   "Summary of the front matter",
+			input:  header + "summary: Summary\n---\n" + body,
+			expect: "Summary",
+		},
+		{
+			desc:   "First paragraph of the content",
+			input:  header + "---\n" + body,
+			expect: "Generate a TwitterCard image for your Hugo posts.",
+		},
+		{
+			desc:   "Truncated first paragraph",
+			input:  header + "---\n" + body,
+			opts:   []ParseOption{WithDescriptionLength(18)},
+			expect: "Generate a Twitter…",
+		},
+		{
+			desc:   "Configured keys",
+			input:  header + "params:\n  lead: Lead\n---\n" + body,
+			opts:   []ParseOption{WithFieldKeys(FieldKeys{Description: []string{"params.lead"}})},
+			expect: "Lead",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fm, err := parseFrontMatter(io.Discard, strings.NewReader(tc.input), time.Now(), tc.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fm.Description != tc.expect {
+				t.Errorf("want %q, but got %q", tc.expect, fm.Description)
+			}
+		})
+	}
+}
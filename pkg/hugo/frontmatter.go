@@ -3,7 +3,9 @@ package hugo
 import (
 	"fmt"
 	"io"
+	"math"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,72 +14,292 @@ import (
 )
 
 const (
-	fmTitle      = "title"
-	fmAuthor     = "author"
-	fmCategories = "categories"
-	fmTags       = "tags"
+	fmTitle       = "title"
+	fmAuthor      = "author"
+	fmCategories  = "categories"
+	fmTags        = "tags"
+	fmDescription = "description"
+	fmSummary     = "summary"
 
 	fmDate        = "date"        // priority high
 	fmLastmod     = "lastmod"     // priority middle
 	fmPublishDate = "publishDate" // priority low
 )
 
+// timeFormats are the layouts of the dates which Hugo accepts.
 var timeFormats = []string{
 	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -07:00",
+	time.DateTime,
 	time.DateOnly,
 }
 
+// FieldKeys is a set of the front matter keys which are looked up for each field.
+// The keys are looked up in order until the non-empty value is found,
+// and a dotted key (e.g. "params.authors") refers to the nested value.
+type FieldKeys struct {
+	Title    []string `json:"title,omitempty"`
+	Author   []string `json:"author,omitempty"`
+	Category []string `json:"category,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Date     []string `json:"date,omitempty"`
+	// Description is optional, and the first paragraph of the content is used if it is not found.
+	Description []string `json:"description,omitempty"`
+}
+
+// DefaultFieldKeys is the front matter keys which Hugo uses.
+var DefaultFieldKeys = FieldKeys{
+	Title:       []string{fmTitle},
+	Author:      []string{fmAuthor},
+	Category:    []string{fmCategories},
+	Tags:        []string{fmTags},
+	Date:        []string{fmDate, fmLastmod, fmPublishDate},
+	Description: []string{fmDescription, fmSummary},
+}
+
+// Defaulting sets the default keys to the empty fields.
+func (k *FieldKeys) Defaulting() {
+	k.Complement(DefaultFieldKeys)
+}
+
+// Complement sets the keys of the other to the empty fields.
+func (k *FieldKeys) Complement(other FieldKeys) {
+	if len(k.Title) == 0 {
+		k.Title = other.Title
+	}
+	if len(k.Author) == 0 {
+		k.Author = other.Author
+	}
+	if len(k.Category) == 0 {
+		k.Category = other.Category
+	}
+	if len(k.Tags) == 0 {
+		k.Tags = other.Tags
+	}
+	if len(k.Date) == 0 {
+		k.Date = other.Date
+	}
+	if len(k.Description) == 0 {
+		k.Description = other.Description
+	}
+}
+
 type FrontMatter struct {
 	Title    string
 	Author   string
 	Category string
 	Tags     []string
 	Date     time.Time
+	// Lastmod and PublishDate are the last modified and published dates, which are the Date if the
+	// front matter does not have them.
+	Lastmod     time.Time `json:",omitzero"`
+	PublishDate time.Time `json:",omitzero"`
+	// Description is the description of the front matter, or the first paragraph of the content.
+	Description string `json:",omitempty"`
+	// WordCount is the number of the words in the content, where each Chinese and Japanese
+	// character is counted as a word.
+	WordCount int `json:",omitempty"`
+	// ReadingTime is the minutes to read the content, or the readingTime of the front matter.
+	ReadingTime int `json:",omitempty"`
+	// Params holds all front matter values to refer to the custom fields.
+	Params map[string]interface{}
+	// Lang is the language of the content. It is empty if the language is not detected.
+	Lang string `json:",omitempty"`
+	// Site is the site configuration which provides the default values.
+	Site *SiteConfig `json:"-"`
+}
+
+// Param returns the front matter value of the key. A dotted key refers to the nested value.
+// If the front matter does not have the key, the site parameter of the key is returned as Hugo does.
+func (fm *FrontMatter) Param(key string) interface{} {
+	if v := fm.PageParam(key); v != nil {
+		return v
+	}
+	if fm.Site != nil {
+		v, _ := lookup(fm.Site.Params, strings.ToLower(strings.TrimPrefix(key, "params.")))
+		return v
+	}
+	return nil
+}
+
+// PageParam returns the front matter value of the key like Param, but it does not fall back to the
+// site parameter. It is used to write the value back into the front matter of the page.
+func (fm *FrontMatter) PageParam(key string) interface{} {
+	v, _ := lookup(fm.Params, key)
+	return v
+}
+
+// SetSite sets the site configuration, and the site author if the author is empty.
+func (fm *FrontMatter) SetSite(site *SiteConfig) {
+	fm.Site = site
+	if fm.Author == "" && site != nil {
+		fm.Author = site.Author
+	}
+}
+
+// Updated reports whether the content is modified on a later day than the date.
+func (fm *FrontMatter) Updated() bool {
+	y, m, d := fm.Date.Date()
+	date := time.Date(y, m, d, 0, 0, 0, 0, fm.Date.Location())
+	return !fm.Lastmod.In(fm.Date.Location()).Before(date.AddDate(0, 0, 1))
+}
+
+// SetLocation converts the dates to the time zone to render them.
+func (fm *FrontMatter) SetLocation(loc *time.Location) {
+	fm.Date = fm.Date.In(loc)
+	fm.Lastmod = fm.Lastmod.In(loc)
+	fm.PublishDate = fm.PublishDate.In(loc)
+}
+
+type parseOptions struct {
+	keys  FieldKeys
+	site  *SiteConfig
+	loc   *time.Location
+	speed ReadingSpeed
+	// descLen is the maximum number of characters of the description from the content
+	descLen int
+	// langs are the languages which are accepted as the codes in the filenames
+	langs []string
+}
+
+// location returns the time zone of the dates, or nil if it is not configured.
+func (o *parseOptions) location() *time.Location {
+	if o.loc != nil {
+		return o.loc
+	}
+	if o.site != nil {
+		return o.site.location
+	}
+	return nil
+}
+
+type ParseOption func(*parseOptions)
+
+// WithFieldKeys sets the front matter keys which are looked up for each field.
+func WithFieldKeys(keys FieldKeys) ParseOption {
+	return func(o *parseOptions) {
+		o.keys = keys
+		o.keys.Defaulting()
+	}
+}
+
+// WithSiteConfig sets the site configuration which provides the site author, parameters, and
+// time zone of the dates which do not have the offset.
+func WithSiteConfig(site *SiteConfig) ParseOption {
+	return func(o *parseOptions) {
+		o.site = site
+	}
+}
+
+// WithLanguages sets the languages which are accepted as the language codes in the filenames in
+// addition to the languages which locale supports. They are ignored if the site configures the languages.
+func WithLanguages(langs ...string) ParseOption {
+	return func(o *parseOptions) {
+		o.langs = langs
+	}
+}
+
+// WithLocation sets the time zone which interprets the dates without the offset and renders the dates.
+// It takes precedence over the time zone of the site configuration.
+func WithLocation(loc *time.Location) ParseOption {
+	return func(o *parseOptions) {
+		o.loc = loc
+	}
+}
+
+// WithReadingSpeed sets the reading speed which computes the reading time of the content.
+func WithReadingSpeed(speed ReadingSpeed) ParseOption {
+	return func(o *parseOptions) {
+		o.speed = speed
+		o.speed.Defaulting()
+	}
+}
+
+// WithDescriptionLength sets the maximum number of characters of the description which is taken
+// from the first paragraph of the content.
+func WithDescriptionLength(n int) ParseOption {
+	return func(o *parseOptions) {
+		if n > 0 {
+			o.descLen = n
+		}
+	}
 }
 
 // ParseFrontMatter parses the frontmatter of the specified Hugo content.
-func ParseFrontMatter(w io.Writer, filename string, currentTime time.Time) (*FrontMatter, error) {
+func ParseFrontMatter(w io.Writer, filename string, currentTime time.Time, opts ...ParseOption) (*FrontMatter, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	return parseFrontMatter(w, file, currentTime)
+	fm, err := parseFrontMatter(w, file, currentTime, opts...)
+	if err != nil {
+		return nil, err
+	}
+	o := newParseOptions(opts)
+	fm.Lang = ContentLang(filename, fm.Site, o.langs)
+	return fm, nil
 }
 
-func parseFrontMatter(w io.Writer, r io.Reader, currentTime time.Time) (*FrontMatter, error) {
+func newParseOptions(opts []ParseOption) parseOptions {
+	o := parseOptions{keys: DefaultFieldKeys, speed: DefaultReadingSpeed, descLen: DefaultDescriptionLength}
+	for _, f := range opts {
+		f(&o)
+	}
+	return o
+}
+
+func parseFrontMatter(w io.Writer, r io.Reader, currentTime time.Time, opts ...ParseOption) (*FrontMatter, error) {
+	o := newParseOptions(opts)
+
 	cfm, err := pageparser.ParseFrontMatterAndContent(r)
 	if err != nil {
 		return nil, err
 	}
 
-	fm := &FrontMatter{}
-	if fm.Title, err = getString(&cfm, fmTitle); err != nil {
+	fm := &FrontMatter{Params: cfm.FrontMatter}
+	if fm.Title, err = getFieldString(cfm.FrontMatter, o.keys.Title); err != nil {
 		return nil, err
 	}
-	if isArray := isArray(&cfm, fmAuthor); isArray {
-		if fm.Author, err = getFirstStringItem(&cfm, fmAuthor); err != nil {
-			return nil, err
-		}
-	} else {
-		if fm.Author, err = getString(&cfm, fmAuthor); err != nil {
+	if fm.Author, err = getFieldString(cfm.FrontMatter, o.keys.Author); err != nil {
+		var fe *FMNotExistError
+		if !errors.As(err, &fe) || o.site == nil || o.site.Author == "" {
 			return nil, err
 		}
 	}
-	if isArray := isArray(&cfm, fmCategories); isArray {
-		if fm.Category, err = getFirstStringItem(&cfm, fmCategories); err != nil {
-			return nil, err
-		}
-	} else {
-		if fm.Category, err = getString(&cfm, fmCategories); err != nil {
+	fm.SetSite(o.site)
+	if fm.Category, err = getFieldString(cfm.FrontMatter, o.keys.Category); err != nil {
+		return nil, err
+	}
+	if fm.Tags, err = getFieldStringItems(cfm.FrontMatter, o.keys.Tags); err != nil {
+		return nil, err
+	}
+	if fm.Description, err = getFieldString(cfm.FrontMatter, o.keys.Description); err != nil {
+		var fe *FMNotExistError
+		if !errors.As(err, &fe) {
 			return nil, err
 		}
+		fm.Description = truncate(firstParagraph(cfm.Content), o.descLen)
 	}
-	if fm.Tags, err = getAllStringItems(&cfm, fmTags); err != nil {
+	words, cjk := countWords(plainText(cfm.Content))
+	fm.WordCount = words + cjk
+	if fm.ReadingTime, err = getReadingTime(cfm.FrontMatter, o.speed.readingTime(words, cjk)); err != nil {
 		return nil, err
 	}
-	if fm.Date, err = getContentDate(&cfm, currentTime); err != nil {
+	loc := o.location()
+	if loc == nil {
+		loc = time.UTC
+	}
+	dates, err := getContentDate(cfm.FrontMatter, o.keys.Date, currentTime, loc)
+	fm.Date, fm.Lastmod, fm.PublishDate = dates.date, dates.lastmod, dates.publishDate
+	if o.location() != nil {
+		fm.SetLocation(loc)
+	}
+	if err != nil {
 		var fe *FMNotExistError
 		if errors.As(err, &fe) {
 			fmt.Fprintf(w, "WARN: %s\n", err.Error())
@@ -89,43 +311,144 @@ func parseFrontMatter(w io.Writer, r io.Reader, currentTime time.Time) (*FrontMa
 	return fm, nil
 }
 
-func getContentDate(cfm *pageparser.ContentFrontMatter, currentTime time.Time) (time.Time, error) {
-	for _, key := range []string{fmDate, fmLastmod, fmPublishDate} {
-		t, err := getTime(cfm, key, currentTime)
-		if err != nil {
-			switch err.(type) {
-			case *FMNotExistError:
-				continue
-			}
+// getFieldString returns the first non-empty string of the keys.
+// If the value is an array, its first item is used.
+func getFieldString(fm map[string]interface{}, keys []string) (string, error) {
+	for _, key := range keys {
+		var (
+			s   string
+			err error
+		)
+		if isArray(fm, key) {
+			s, err = getFirstStringItem(fm, key)
+		} else {
+			s, err = getString(fm, key)
+		}
+		if _, ok := err.(*FMNotExistError); ok {
+			continue
+		}
+		return s, err
+	}
+	return "", NewFMNotExistError(strings.Join(keys, ", "))
+}
+
+// getFieldStringItems returns the first non-empty string array of the keys.
+func getFieldStringItems(fm map[string]interface{}, keys []string) ([]string, error) {
+	for _, key := range keys {
+		arr, err := getAllStringItems(fm, key)
+		if _, ok := err.(*FMNotExistError); ok {
+			continue
+		}
+		return arr, err
+	}
+	return nil, NewFMNotExistError(strings.Join(keys, ", "))
+}
+
+// contentDates are the dates of the content.
+type contentDates struct {
+	date        time.Time
+	lastmod     time.Time
+	publishDate time.Time
+}
+
+// getContentDate returns the first date of the keys, and the last modified and published dates
+// which are the date if the front matter does not have them. The date without the offset is in
+// the location. If no keys are found, all dates are the current time.
+func getContentDate(fm map[string]interface{}, keys []string, currentTime time.Time, loc *time.Location) (contentDates, error) {
+	dates := contentDates{date: currentTime, lastmod: currentTime, publishDate: currentTime}
+	var err error
+	if dates.date, err = getFirstTime(fm, keys, currentTime, loc); err != nil {
+		return dates, err
+	}
+	if dates.lastmod, err = getOptionalTime(fm, fmLastmod, dates.date, loc); err != nil {
+		return dates, err
+	}
+	dates.publishDate, err = getOptionalTime(fm, fmPublishDate, dates.date, loc)
+	return dates, err
+}
+
+// getFirstTime returns the first time of the keys.
+func getFirstTime(fm map[string]interface{}, keys []string, currentTime time.Time, loc *time.Location) (time.Time, error) {
+	for _, key := range keys {
+		t, err := getTime(fm, key, currentTime, loc)
+		if _, ok := err.(*FMNotExistError); ok {
+			continue
 		}
 		return t, err
 	}
-	return currentTime, NewFMNotExistError(
-		strings.Join([]string{fmDate, fmLastmod, fmPublishDate}, ", "))
+	return currentTime, NewFMNotExistError(strings.Join(keys, ", "))
 }
 
-func getTime(cfm *pageparser.ContentFrontMatter, fmKey string, currentTIme time.Time) (t time.Time, err error) {
-	v, ok := cfm.FrontMatter[fmKey]
+// getOptionalTime returns the time of the key, or the default time if the key does not exist.
+func getOptionalTime(fm map[string]interface{}, key string, def time.Time, loc *time.Location) (time.Time, error) {
+	t, err := getTime(fm, key, def, loc)
+	if _, ok := err.(*FMNotExistError); ok {
+		return def, nil
+	}
+	return t, err
+}
+
+func getTime(fm map[string]interface{}, fmKey string, currentTIme time.Time, loc *time.Location) (time.Time, error) {
+	v, ok := lookup(fm, fmKey)
 	if !ok {
 		return currentTIme, NewFMNotExistError(fmKey)
 	}
-	switch tstr := v.(type) {
+	var s string
+	switch t := v.(type) {
 	case string:
-		for _, layout := range timeFormats {
-			if t, err = time.Parse(layout, tstr); err == nil {
-				return t, nil
-			}
-		}
-		return currentTIme, fmt.Errorf("failed to parse time: %s, supported formats are %s", err, strings.Join(timeFormats, ", "))
+		s = t
 	case time.Time:
-		return tstr, nil
+		return t, nil
+	case fmt.Stringer:
+		// TOML local dates and date-times do not have the offset
+		s = t.String()
 	default:
 		return currentTIme, NewFMInvalidTypeError(fmKey, "time.Time or string", t)
 	}
+	t, err := ParseTime(s, loc)
+	if err != nil {
+		return currentTIme, err
+	}
+	return t, nil
+}
+
+// ParseTime parses the time in the formats which Hugo accepts. The time without the offset is in the location.
+func ParseTime(s string, loc *time.Location) (time.Time, error) {
+	var err error
+	for _, layout := range timeFormats {
+		var t time.Time
+		if t, err = time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to parse time: %s, supported formats are %s", err, strings.Join(timeFormats, ", "))
 }
 
-func getString(cfm *pageparser.ContentFrontMatter, fmKey string) (string, error) {
-	v, ok := cfm.FrontMatter[fmKey]
+// getReadingTime returns the minutes of the readingTime, or the computed minutes if it does not exist.
+func getReadingTime(fm map[string]interface{}, computed int) (int, error) {
+	v, ok := lookup(fm, fmReadingTime)
+	if !ok {
+		return computed, nil
+	}
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case int64:
+		return int(n), nil
+	case uint64:
+		return int(n), nil
+	case float64:
+		return int(math.Ceil(n)), nil
+	case string:
+		if i, err := strconv.Atoi(strings.TrimSpace(n)); err == nil {
+			return i, nil
+		}
+	}
+	return computed, NewFMInvalidTypeError(fmReadingTime, "number", v)
+}
+
+func getString(fm map[string]interface{}, fmKey string) (string, error) {
+	v, ok := lookup(fm, fmKey)
 	if !ok {
 		return "", NewFMNotExistError(fmKey)
 	}
@@ -141,8 +464,8 @@ func getString(cfm *pageparser.ContentFrontMatter, fmKey string) (string, error)
 	}
 }
 
-func getAllStringItems(cfm *pageparser.ContentFrontMatter, fmKey string) ([]string, error) {
-	v, ok := cfm.FrontMatter[fmKey]
+func getAllStringItems(fm map[string]interface{}, fmKey string) ([]string, error) {
+	v, ok := lookup(fm, fmKey)
 	if !ok {
 		return nil, NewFMNotExistError(fmKey)
 	}
@@ -170,19 +493,40 @@ func getAllStringItems(cfm *pageparser.ContentFrontMatter, fmKey string) ([]stri
 	}
 }
 
-func getFirstStringItem(cfm *pageparser.ContentFrontMatter, fmKey string) (string, error) {
-	arr, err := getAllStringItems(cfm, fmKey)
+func getFirstStringItem(fm map[string]interface{}, fmKey string) (string, error) {
+	arr, err := getAllStringItems(fm, fmKey)
 	if err != nil {
 		return "", err
 	}
 	return arr[0], nil
 }
 
-func isArray(cfm *pageparser.ContentFrontMatter, fmKey string) bool {
-	switch v := cfm.FrontMatter[fmKey]; v.(type) {
+func isArray(fm map[string]interface{}, fmKey string) bool {
+	v, _ := lookup(fm, fmKey)
+	switch v.(type) {
 	case []interface{}:
 		return true
 	default:
 		return false
 	}
 }
+
+// lookup returns the value of the key. A dotted key refers to the nested value.
+func lookup(fm map[string]interface{}, fmKey string) (interface{}, bool) {
+	if v, ok := fm[fmKey]; ok {
+		return v, true
+	}
+
+	keys := strings.Split(fmKey, ".")
+	var v interface{} = fm
+	for _, k := range keys {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[k]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
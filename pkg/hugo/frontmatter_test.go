@@ -29,11 +29,23 @@ categories: ["program"]
 ---
 content`,
 			expectFM: &FrontMatter{
-				Title:    "HugoでもTwitterCardを自動生成したい",
-				Author:   "@Ladicle",
-				Category: "program",
-				Tags:     []string{"hugo", "go", "OGP"},
-				Date:     mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				Title:       "HugoでもTwitterCardを自動生成したい",
+				Author:      "@Ladicle",
+				Category:    "program",
+				Tags:        []string{"hugo", "go", "OGP"},
+				Date:        mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				Lastmod:     mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				PublishDate: mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				Description: "content",
+				WordCount:   1,
+				ReadingTime: 1,
+				Params: map[string]interface{}{
+					"title":      "HugoでもTwitterCardを自動生成したい",
+					"author":     []interface{}{"@Ladicle"},
+					"date":       "2020-06-21T03:56:24+09:00",
+					"tags":       []interface{}{"hugo", "go", "OGP"},
+					"categories": []interface{}{"program"},
+				},
 			},
 		},
 		{
@@ -47,11 +59,23 @@ categories = ["program"]
 +++
 content`,
 			expectFM: &FrontMatter{
-				Title:    "HugoでもTwitterCardを自動生成したい",
-				Author:   "@Ladicle",
-				Category: "program",
-				Tags:     []string{"hugo", "go", "OGP"},
-				Date:     mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				Title:       "HugoでもTwitterCardを自動生成したい",
+				Author:      "@Ladicle",
+				Category:    "program",
+				Tags:        []string{"hugo", "go", "OGP"},
+				Date:        mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				Lastmod:     mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				PublishDate: mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				Description: "content",
+				WordCount:   1,
+				ReadingTime: 1,
+				Params: map[string]interface{}{
+					"title":      "HugoでもTwitterCardを自動生成したい",
+					"author":     []interface{}{"@Ladicle"},
+					"date":       "2020-06-21T03:56:24+09:00",
+					"tags":       []interface{}{"hugo", "go", "OGP"},
+					"categories": []interface{}{"program"},
+				},
 			},
 		},
 		{
@@ -113,11 +137,19 @@ categories = ["cat11"]
 tags = ["tag1"]
 +++`,
 			expectFM: &FrontMatter{
-				Title:    "Title",
-				Author:   "@Ladicle",
-				Category: "cat11",
-				Tags:     []string{"tag1"},
-				Date:     currentTime,
+				Title:       "Title",
+				Author:      "@Ladicle",
+				Category:    "cat11",
+				Tags:        []string{"tag1"},
+				Date:        currentTime,
+				Lastmod:     currentTime,
+				PublishDate: currentTime,
+				Params: map[string]interface{}{
+					"title":      "Title",
+					"author":     []interface{}{"@Ladicle"},
+					"tags":       []interface{}{"tag1"},
+					"categories": []interface{}{"cat11"},
+				},
 			},
 		},
 	}
@@ -147,6 +179,151 @@ tags = ["tag1"]
 	}
 }
 
+func TestParseFrontMatterWithFieldKeys(t *testing.T) {
+	currentTime := time.Now()
+	keys := FieldKeys{
+		Title:  []string{"ogTitle", "title"},
+		Author: []string{"params.authors", "author"},
+	}
+
+	testCases := []struct {
+		desc         string
+		input        string
+		expectTitle  string
+		expectAuthor string
+		expectErr    error
+	}{
+		{
+			desc: "Use the first key",
+			input: `---
+title: "Title"
+ogTitle: "OG Title"
+params:
+  authors: ["@Ladicle", "@Other"]
+categories: ["program"]
+tags: ["go"]
+---`,
+			expectTitle:  "OG Title",
+			expectAuthor: "@Ladicle",
+		},
+		{
+			desc: "Fall back to the next key",
+			input: `+++
+title = "Title"
+author = "@Ladicle"
+categories = ["program"]
+tags = ["go"]
++++`,
+			expectTitle:  "Title",
+			expectAuthor: "@Ladicle",
+		},
+		{
+			desc: "All keys are missing",
+			input: `+++
+title = "Title"
++++`,
+			expectErr: NewFMNotExistError("params.authors, author"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fm, err := parseFrontMatter(os.Stdout, strings.NewReader(tc.input), currentTime, WithFieldKeys(keys))
+			if err != nil {
+				if tc.expectErr != nil && tc.expectErr.Error() == err.Error() {
+					return
+				}
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if tc.expectErr != nil {
+				t.Fatalf("expect to occur %+v error but it didn't", tc.expectErr)
+			}
+			if fm.Title != tc.expectTitle || fm.Author != tc.expectAuthor {
+				t.Fatalf("parseFrontMatter() returns unexpected value: got=%q/%q, want=%q/%q",
+					fm.Title, fm.Author, tc.expectTitle, tc.expectAuthor)
+			}
+		})
+	}
+}
+
+func TestParseFrontMatterDates(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		input         string
+		expectDate    string
+		expectLastmod string
+		expectPublish string
+		expectUpdated bool
+	}{
+		{
+			desc: "All dates",
+			input: `---
+title: Title
+author: "@Ladicle"
+categories: [program]
+tags: [go]
+date: 2020-06-21T03:56:24+09:00
+lastmod: 2020-07-01T10:00:00+09:00
+publishDate: 2020-06-22T00:00:00+09:00
+---`,
+			expectDate:    "2020-06-21T03:56:24+09:00",
+			expectLastmod: "2020-07-01T10:00:00+09:00",
+			expectPublish: "2020-06-22T00:00:00+09:00",
+			expectUpdated: true,
+		},
+		{
+			desc: "Modified on the same day",
+			input: `---
+title: Title
+author: "@Ladicle"
+categories: [program]
+tags: [go]
+date: 2020-06-21T03:56:24+09:00
+lastmod: 2020-06-21T23:00:00+09:00
+---`,
+			expectDate:    "2020-06-21T03:56:24+09:00",
+			expectLastmod: "2020-06-21T23:00:00+09:00",
+			expectPublish: "2020-06-21T03:56:24+09:00",
+		},
+		{
+			desc: "Only lastmod",
+			input: `---
+title: Title
+author: "@Ladicle"
+categories: [program]
+tags: [go]
+lastmod: 2020-07-01T10:00:00+09:00
+---`,
+			expectDate:    "2020-07-01T10:00:00+09:00",
+			expectLastmod: "2020-07-01T10:00:00+09:00",
+			expectPublish: "2020-07-01T10:00:00+09:00",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fm, err := parseFrontMatter(os.Stdout, strings.NewReader(tc.input), time.Now())
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			for _, d := range []struct {
+				name   string
+				got    time.Time
+				expect string
+			}{
+				{"date", fm.Date, tc.expectDate},
+				{"lastmod", fm.Lastmod, tc.expectLastmod},
+				{"publishDate", fm.PublishDate, tc.expectPublish},
+			} {
+				if want := mustParseRFC3339(t, d.expect); !d.got.Equal(want) {
+					t.Errorf("%s: want %v, but got %v", d.name, want, d.got)
+				}
+			}
+			if got := fm.Updated(); got != tc.expectUpdated {
+				t.Errorf("Updated() returns %v, want %v", got, tc.expectUpdated)
+			}
+		})
+	}
+}
+
 func mustParseRFC3339(t *testing.T, timeStr string) time.Time {
 	tt, err := time.Parse(time.RFC3339, timeStr)
 	if err != nil {
@@ -0,0 +1,74 @@
+package hugo
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Ladicle/tcardgen/pkg/locale"
+)
+
+// langPattern matches the language code in the filename such as "ja" of "post.ja.md".
+var langPattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]+)?$`)
+
+// SplitLang splits the filename into the name and language code. The extension is removed.
+// The code is split only if isLang returns true for it, so that "node.js.md" is not in "js".
+func SplitLang(filename string, isLang func(string) bool) (string, string) {
+	name := strings.TrimSuffix(filename, filepath.Ext(filename))
+	if i := strings.LastIndex(name, "."); i > 0 && langPattern.MatchString(name[i+1:]) && isLang(name[i+1:]) {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+// LangMatcher returns the function which reports whether the code in the filename is a language.
+// When the site configures the languages, the code must be one of them. Otherwise, it must be one
+// of the langs, which have the drawing configuration, or a language which locale supports.
+func LangMatcher(site *SiteConfig, langs []string) func(string) bool {
+	return func(code string) bool {
+		if site != nil && len(site.Languages) != 0 {
+			return site.hasLanguage(code)
+		}
+		for _, l := range langs {
+			if strings.EqualFold(l, code) {
+				return true
+			}
+		}
+		return locale.IsSupported(code)
+	}
+}
+
+// ContentLang returns the language of the content file as Hugo detects it. The language is the
+// code in the filename such as "ja" of "foo.ja.md", or the language whose content directory has
+// the file. Otherwise, it is the default content language or language code of the site.
+// The code in the filename must be a language of LangMatcher with the site and langs.
+func ContentLang(filename string, site *SiteConfig, langs []string) string {
+	_, lang := SplitLang(filepath.Base(filename), LangMatcher(site, langs))
+	if site == nil || lang != "" {
+		return lang
+	}
+
+	if abs, err := filepath.Abs(filename); err == nil {
+		for l, sl := range site.Languages {
+			if sl.ContentDir == "" {
+				continue
+			}
+			dir, err := filepath.Abs(filepath.Join(site.Dir, sl.ContentDir))
+			if err != nil {
+				continue
+			}
+			if rel, err := filepath.Rel(dir, abs); err == nil && filepath.IsLocal(rel) {
+				return l
+			}
+		}
+	}
+	if site.DefaultContentLanguage != "" {
+		return site.DefaultContentLanguage
+	}
+	return site.LanguageCode
+}
+
+func (s *SiteConfig) hasLanguage(lang string) bool {
+	_, ok := s.Languages[strings.ToLower(lang)]
+	return ok
+}
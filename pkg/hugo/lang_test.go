@@ -0,0 +1,60 @@
+package hugo
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSplitLang(t *testing.T) {
+	for filename, expect := range map[string][2]string{
+		"foo.md":         {"foo", ""},
+		"foo.ja.md":      {"foo", "ja"},
+		"index.zh-cn.md": {"index", "zh-cn"},
+		"v1.2.md":        {"v1.2", ""},
+		"my.post.md":     {"my.post", ""},
+		"_index.en.html": {"_index", "en"},
+		".hidden.md":     {".hidden", ""},
+		"node.js.md":     {"node.js", ""},
+		"foo.eo.md":      {"foo", "eo"},
+	} {
+		name, lang := SplitLang(filename, LangMatcher(nil, []string{"eo"}))
+		if name != expect[0] || lang != expect[1] {
+			t.Errorf("%s: want %q, but got (%q, %q)", filename, expect, name, lang)
+		}
+	}
+}
+
+func TestContentLang(t *testing.T) {
+	site := &SiteConfig{Dir: "site", LanguageCode: "en-us"}
+	multilingual := &SiteConfig{
+		Dir:                    "site",
+		DefaultContentLanguage: "en",
+		Languages: map[string]SiteLanguage{
+			"en": {ContentDir: "content/en"},
+			"ja": {ContentDir: "content/ja"},
+		},
+	}
+	testCases := []struct {
+		desc     string
+		filename string
+		site     *SiteConfig
+		langs    []string
+		expect   string
+	}{
+		{desc: "Filename without site", filename: "foo.ja.md", expect: "ja"},
+		{desc: "No language without site", filename: "foo.md", expect: ""},
+		{desc: "Unsupported language without site", filename: "node.js.md", expect: ""},
+		{desc: "Configured language without site", filename: "foo.eo.md", langs: []string{"eo"}, expect: "eo"},
+		{desc: "Unsupported language with site", filename: "site/content/node.js.md", site: site, expect: "en-us"},
+		{desc: "Filename with site", filename: "site/content/foo.fr.md", site: site, expect: "fr"},
+		{desc: "Language code of site", filename: "site/content/foo.md", site: site, expect: "en-us"},
+		{desc: "Content directory", filename: "site/content/ja/posts/foo.md", site: multilingual, expect: "ja"},
+		{desc: "Unknown language in filename", filename: "site/content/ja/foo.fr.md", site: multilingual, expect: "ja"},
+		{desc: "Default content language", filename: "other/foo.md", site: multilingual, expect: "en"},
+	}
+	for _, tc := range testCases {
+		if got := ContentLang(filepath.FromSlash(tc.filename), tc.site, tc.langs); got != tc.expect {
+			t.Errorf("%s: want %q, but got %q", tc.desc, tc.expect, got)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package hugo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/parser/metadecoders"
+)
+
+// DefaultContentDir is the content directory of the site which does not configure it.
+const DefaultContentDir = "content"
+
+// ErrSiteConfigNotFound means the directory has no Hugo site configuration.
+var ErrSiteConfigNotFound = errors.New("site configuration is not found")
+
+// ErrNotSiteConfig means the legacy config.* file is not a Hugo site configuration, such as the
+// drawing configuration of tcardgen.
+var ErrNotSiteConfig = errors.New("not a Hugo site configuration")
+
+// siteConfigNames are the names of the site configuration files in the order Hugo looks them up.
+var siteConfigNames = []string{"hugo", "config"}
+
+// siteConfigExts are the extensions of the site configuration files.
+var siteConfigExts = []string{".toml", ".yaml", ".yml", ".json"}
+
+// siteConfigKeys are the lower-case keys which only the Hugo site configuration has. The config.*
+// file which has none of them is not the site configuration.
+var siteConfigKeys = []string{
+	"author", "baseurl", "canonifyurls", "contentdir", "copyright", "defaultcontentlanguage",
+	"disablekinds", "enablegitinfo", "enablerobotstxt", "languagecode", "markup", "menu", "menus",
+	"module", "outputs", "paginate", "pagination", "params", "permalinks", "publishdir", "staticdir",
+	"taxonomies", "theme",
+}
+
+// SiteConfig is the Hugo site configuration which provides the default values of the contents.
+type SiteConfig struct {
+	// Dir is the site directory.
+	Dir          string `json:"-"`
+	Author       string `json:"author,omitempty"`
+	LanguageCode string `json:"languageCode,omitempty"`
+	TimeZone     string `json:"timeZone,omitempty"`
+	// DefaultContentLanguage is the language of the contents which do not have the language code.
+	DefaultContentLanguage string `json:"defaultContentLanguage,omitempty"`
+	// Languages are the languages of the multilingual site by the language keys.
+	Languages map[string]SiteLanguage `json:"languages,omitempty"`
+	// ContentDir is the content directory which is relative to the site directory.
+	ContentDir string `json:"contentDir,omitempty"`
+	// Taxonomies maps the singular names to the plural names which are the front matter keys.
+	Taxonomies map[string]string `json:"taxonomies,omitempty"`
+	// Params holds the site parameters. Keys are lower case as Hugo does.
+	Params map[string]interface{} `json:"params,omitempty"`
+
+	location *time.Location
+}
+
+// SiteLanguage is the configuration of a language of the multilingual site.
+type SiteLanguage struct {
+	LanguageCode string `json:"languageCode,omitempty"`
+	// ContentDir is the content directory of the language which is relative to the site directory.
+	ContentDir string `json:"contentDir,omitempty"`
+}
+
+// LoadSiteConfig loads the site configuration in the directory. It reads the root configuration file
+// (hugo.toml, hugo.yaml, hugo.json, or legacy config.*) and the files in the config/_default directory,
+// where the values in the root configuration file take precedence. The ignored files such as the
+// drawing configuration are never read as the site configuration.
+func LoadSiteConfig(dir string, ignores ...string) (*SiteConfig, error) {
+	l := configLoader{ignores: ignores}
+	conf, err := l.loadConfigDir(filepath.Join(dir, "config", "_default"))
+	if err != nil {
+		return nil, err
+	}
+	found := conf != nil
+	for _, name := range siteConfigNames {
+		m, filename, err := l.loadConfigFile(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		if m != nil && name == "config" && !isSiteConfig(m) {
+			return nil, fmt.Errorf("%w: %q", ErrNotSiteConfig, filename)
+		}
+		if m != nil {
+			conf = mergeMaps(m, conf)
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%w in %q", ErrSiteConfigNotFound, dir)
+	}
+	site, err := newSiteConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	site.Dir = dir
+	return site, nil
+}
+
+// configLoader loads the configuration files except the ignored files.
+type configLoader struct {
+	ignores []string
+}
+
+// loadConfigFile loads the first configuration file of the name and returns it with the filename,
+// or returns nil if it does not exist.
+func (l *configLoader) loadConfigFile(dir, name string) (map[string]interface{}, string, error) {
+	for _, ext := range siteConfigExts {
+		filename := filepath.Join(dir, name+ext)
+		if l.isIgnored(filename) {
+			continue
+		}
+		b, err := os.ReadFile(filename)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, "", err
+		}
+		m, err := metadecoders.Default.UnmarshalToMap(b, metadecoders.FormatFromString(ext))
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse %q: %w", filename, err)
+		}
+		return lowerKeys(m), filename, nil
+	}
+	return nil, "", nil
+}
+
+// isIgnored returns true if the file is one of the ignored files.
+func (l *configLoader) isIgnored(filename string) bool {
+	if len(l.ignores) == 0 {
+		return false
+	}
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return false
+	}
+	for _, ignore := range l.ignores {
+		if ii, err := os.Stat(ignore); err == nil && os.SameFile(fi, ii) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSiteConfig returns true if the configuration has any key of the Hugo site configuration.
+func isSiteConfig(m map[string]interface{}) bool {
+	if s, ok := m["title"].(string); ok && s != "" {
+		// the drawing configuration also has the title, but it is not a string
+		return true
+	}
+	for _, key := range siteConfigKeys {
+		if _, ok := m[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// loadConfigDir loads the configuration directory. The hugo.* or config.* file is the root
+// configuration, and the other file is the configuration of the key of its name (e.g. params.toml).
+// Files for the languages or environments such as menus.en.toml are not supported.
+func (l *configLoader) loadConfigDir(dir string) (map[string]interface{}, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	// sort by name to merge the files deterministically
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var conf map[string]interface{}
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		name := strings.TrimSuffix(e.Name(), ext)
+		if e.IsDir() || !isSiteConfigExt(ext) || strings.Contains(name, ".") {
+			continue
+		}
+		m, _, err := l.loadConfigFile(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		if name != "hugo" && name != "config" {
+			m = map[string]interface{}{strings.ToLower(name): m}
+		}
+		conf = mergeMaps(conf, m)
+	}
+	return conf, nil
+}
+
+func isSiteConfigExt(ext string) bool {
+	for _, e := range siteConfigExts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+func newSiteConfig(conf map[string]interface{}) (*SiteConfig, error) {
+	site := &SiteConfig{ContentDir: DefaultContentDir}
+	site.Params, _ = conf["params"].(map[string]interface{})
+	// the site author is params.author since Hugo v0.124, and the legacy author is also supported
+	site.Author = authorName(site.Params["author"])
+	if site.Author == "" {
+		site.Author = authorName(conf["author"])
+	}
+	site.LanguageCode, _ = conf["languagecode"].(string)
+	if s, _ := conf["contentdir"].(string); s != "" {
+		site.ContentDir = s
+	}
+	if tx, ok := conf["taxonomies"].(map[string]interface{}); ok {
+		site.Taxonomies = make(map[string]string, len(tx))
+		for singular, plural := range tx {
+			if s, ok := plural.(string); ok {
+				site.Taxonomies[singular] = strings.ToLower(s)
+			}
+		}
+	}
+	site.DefaultContentLanguage, _ = conf["defaultcontentlanguage"].(string)
+	if langs, ok := conf["languages"].(map[string]interface{}); ok {
+		site.Languages = make(map[string]SiteLanguage, len(langs))
+		for lang, v := range langs {
+			lc, _ := v.(map[string]interface{})
+			sl := SiteLanguage{}
+			sl.LanguageCode, _ = lc["languagecode"].(string)
+			sl.ContentDir, _ = lc["contentdir"].(string)
+			site.Languages[lang] = sl
+		}
+	}
+	site.TimeZone, _ = conf["timezone"].(string)
+	if site.TimeZone != "" {
+		loc, err := time.LoadLocation(site.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeZone %q: %w", site.TimeZone, err)
+		}
+		site.location = loc
+	}
+	return site, nil
+}
+
+// authorName returns the name of the author, which is a string, a list of names, or a map with the name.
+func authorName(v interface{}) string {
+	switch a := v.(type) {
+	case string:
+		return a
+	case []interface{}:
+		if len(a) != 0 {
+			return authorName(a[0])
+		}
+	case map[string]interface{}:
+		return authorName(a["name"])
+	}
+	return ""
+}
+
+// Location returns the time zone of the site, or UTC if it is not configured.
+func (s *SiteConfig) Location() *time.Location {
+	if s == nil || s.location == nil {
+		return time.UTC
+	}
+	return s.location
+}
+
+// FieldKeys returns the front matter keys of the category and tags fields which are configured
+// as the taxonomies of the site. The keys which are not configured are empty.
+func (s *SiteConfig) FieldKeys() FieldKeys {
+	var keys FieldKeys
+	if s == nil {
+		return keys
+	}
+	if plural, ok := s.Taxonomies["category"]; ok {
+		keys.Category = []string{plural}
+	}
+	if plural, ok := s.Taxonomies["tag"]; ok {
+		keys.Tags = []string{plural}
+	}
+	return keys
+}
+
+// lowerKeys converts the keys of the maps to lower case recursively, because Hugo configuration keys
+// are case-insensitive.
+func lowerKeys(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if child, ok := v.(map[string]interface{}); ok {
+			v = lowerKeys(child)
+		}
+		out[strings.ToLower(k)] = v
+	}
+	return out
+}
+
+// mergeMaps merges the src into the dst recursively. The values in the dst take precedence.
+func mergeMaps(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = map[string]interface{}{}
+	}
+	for k, v := range src {
+		dv, ok := dst[k]
+		if !ok {
+			dst[k] = v
+			continue
+		}
+		dm, dok := dv.(map[string]interface{})
+		sm, sok := v.(map[string]interface{})
+		if dok && sok {
+			dst[k] = mergeMaps(dm, sm)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,270 @@
+package hugo
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadSiteConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		files  map[string]string
+		expect *SiteConfig
+	}{
+		{
+			desc: "Load hugo.toml",
+			files: map[string]string{
+				"hugo.toml": `languageCode = "ja-jp"
+timeZone = "Asia/Tokyo"
+contentDir = "posts"
+[params]
+author = "Ladicle"
+subTitle = "Blog"
+[taxonomies]
+tag = "Tags"
+series = "series"`,
+			},
+			expect: &SiteConfig{
+				Author:       "Ladicle",
+				LanguageCode: "ja-jp",
+				TimeZone:     "Asia/Tokyo",
+				ContentDir:   "posts",
+				Taxonomies:   map[string]string{"tag": "tags", "series": "series"},
+				Params:       map[string]interface{}{"author": "Ladicle", "subtitle": "Blog"},
+			},
+		},
+		{
+			desc: "Load legacy author from config.yaml",
+			files: map[string]string{
+				"config.yaml": "author:\n  name: Ladicle\n",
+			},
+			expect: &SiteConfig{Author: "Ladicle", ContentDir: DefaultContentDir},
+		},
+		{
+			desc: "Load languages",
+			files: map[string]string{
+				"hugo.yaml": `defaultContentLanguage: ja
+languages:
+  ja:
+    languageCode: ja-JP
+    contentDir: content/ja
+  en:
+    contentDir: content/en
+`,
+			},
+			expect: &SiteConfig{
+				ContentDir:             DefaultContentDir,
+				DefaultContentLanguage: "ja",
+				Languages: map[string]SiteLanguage{
+					"ja": {LanguageCode: "ja-JP", ContentDir: "content/ja"},
+					"en": {ContentDir: "content/en"},
+				},
+			},
+		},
+		{
+			desc: "Merge config directory",
+			files: map[string]string{
+				"hugo.json":                   `{"params": {"author": ["Ladicle", "Other"]}}`,
+				"config/_default/hugo.toml":   `languageCode = "en-us"`,
+				"config/_default/params.yaml": "author: Overridden\ndescription: Desc\n",
+				"config/_default/menus.en.toml": `[[main]]
+name = "Home"`,
+			},
+			expect: &SiteConfig{
+				Author:       "Ladicle",
+				LanguageCode: "en-us",
+				ContentDir:   DefaultContentDir,
+				Params:       map[string]interface{}{"author": []interface{}{"Ladicle", "Other"}, "description": "Desc"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tc.files)
+			site, err := LoadSiteConfig(dir)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			site.Dir, site.location = "", nil
+			if !reflect.DeepEqual(site, tc.expect) {
+				t.Fatalf("want %#v, but got %#v", tc.expect, site)
+			}
+		})
+	}
+}
+
+func TestLoadSiteConfigErrors(t *testing.T) {
+	if _, err := LoadSiteConfig(t.TempDir()); !errors.Is(err, ErrSiteConfigNotFound) {
+		t.Errorf("want ErrSiteConfigNotFound, but got %v", err)
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"hugo.toml": `timeZone = "Nowhere/City"`})
+	if _, err := LoadSiteConfig(dir); err == nil {
+		t.Error("want error for invalid time zone")
+	}
+
+	dir = t.TempDir()
+	writeFiles(t, dir, map[string]string{"config.json": `{"name": "app", "port": 8080}`})
+	if _, err := LoadSiteConfig(dir); !errors.Is(err, ErrNotSiteConfig) {
+		t.Errorf("want ErrNotSiteConfig, but got %v", err)
+	}
+}
+
+func TestLoadSiteConfigIgnores(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"config.yaml": "title:\n  start: {px: 123, py: 165}\n",
+		"hugo.toml":   `languageCode = "en-us"`,
+	})
+	drawing := filepath.Join(dir, "config.yaml")
+
+	if _, err := LoadSiteConfig(dir, drawing); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := os.Remove(filepath.Join(dir, "hugo.toml")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSiteConfig(dir, drawing); !errors.Is(err, ErrSiteConfigNotFound) {
+		t.Errorf("want ErrSiteConfigNotFound, but got %v", err)
+	}
+}
+
+func TestParseFrontMatterWithSiteConfig(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	site := &SiteConfig{
+		Author:   "Ladicle",
+		TimeZone: "Asia/Tokyo",
+		Params:   map[string]interface{}{"subtitle": "Blog"},
+		location: loc,
+	}
+	input := `---
+title: Hello
+categories: [program]
+tags: [go]
+date: 2020-06-21
+---
+`
+	fm, err := parseFrontMatter(io.Discard, strings.NewReader(input), time.Now(), WithSiteConfig(site))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fm.Author != "Ladicle" {
+		t.Errorf("want the site author, but got %q", fm.Author)
+	}
+	if want := time.Date(2020, 6, 21, 0, 0, 0, 0, loc); !fm.Date.Equal(want) {
+		t.Errorf("want %v, but got %v", want, fm.Date)
+	}
+	if got := fm.Param("params.subTitle"); got != "Blog" {
+		t.Errorf("want the site parameter, but got %v", got)
+	}
+
+	if _, err := parseFrontMatter(io.Discard, strings.NewReader(input), time.Now()); err == nil {
+		t.Error("want error for missing author without the site configuration")
+	}
+}
+
+func TestParseFrontMatterTimeZone(t *testing.T) {
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	site := &SiteConfig{Author: "Ladicle", TimeZone: "America/New_York"}
+	if site.location, err = time.LoadLocation(site.TimeZone); err != nil {
+		t.Fatal(err)
+	}
+	yaml := func(date string) string {
+		return "---\ntitle: Title\nauthor: Ladicle\ncategories: [go]\ntags: [go]\ndate: " + date + "\n---\n"
+	}
+
+	testCases := []struct {
+		desc   string
+		input  string
+		opts   []ParseOption
+		expect time.Time
+	}{
+		{
+			desc:   "Date only in the time zone",
+			input:  yaml("2020-06-21"),
+			opts:   []ParseOption{WithLocation(jst)},
+			expect: time.Date(2020, 6, 21, 0, 0, 0, 0, jst),
+		},
+		{
+			desc:   "Date and time without the offset",
+			input:  yaml("2020-06-21T23:30:00"),
+			opts:   []ParseOption{WithLocation(jst)},
+			expect: time.Date(2020, 6, 21, 23, 30, 0, 0, jst),
+		},
+		{
+			desc:   "Date and time with the numeric offset",
+			input:  yaml("2020-06-21 23:30:00 -0700"),
+			opts:   []ParseOption{WithLocation(jst)},
+			expect: time.Date(2020, 6, 22, 15, 30, 0, 0, jst),
+		},
+		{
+			desc: "TOML local date",
+			input: `+++
+title = "Title"
+author = "Ladicle"
+categories = ["go"]
+tags = ["go"]
+date = 2020-06-21
++++
+`,
+			opts:   []ParseOption{WithLocation(jst)},
+			expect: time.Date(2020, 6, 21, 0, 0, 0, 0, jst),
+		},
+		{
+			desc:   "UTC is rendered in the time zone",
+			input:  yaml("2020-06-21T15:30:00Z"),
+			opts:   []ParseOption{WithLocation(jst)},
+			expect: time.Date(2020, 6, 22, 0, 30, 0, 0, jst),
+		},
+		{
+			desc:   "Site time zone",
+			input:  yaml("2020-06-21T15:30:00Z"),
+			opts:   []ParseOption{WithSiteConfig(site)},
+			expect: time.Date(2020, 6, 21, 11, 30, 0, 0, site.location),
+		},
+		{
+			desc:   "Time zone takes precedence over the site",
+			input:  yaml("2020-06-21"),
+			opts:   []ParseOption{WithSiteConfig(site), WithLocation(jst)},
+			expect: time.Date(2020, 6, 21, 0, 0, 0, 0, jst),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fm, err := parseFrontMatter(io.Discard, strings.NewReader(tc.input), time.Now(), tc.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !fm.Date.Equal(tc.expect) || fm.Date.Location() != tc.expect.Location() {
+				t.Errorf("want %v, but got %v", tc.expect, fm.Date)
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package hugo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnsupportedFrontMatter means the content does not start with YAML, TOML or JSON front matter.
+var ErrUnsupportedFrontMatter = errors.New("front matter must be YAML, TOML or JSON")
+
+// SetFrontMatterValue sets the value to the key of the front matter, and returns the updated content.
+// A dotted key (e.g. "params.og") refers to the nested value. Only the line or span of the key is
+// rewritten, so the format, the order of keys and the body of the content are kept as they are.
+// The value must be a string or a string slice.
+func SetFrontMatterValue(content []byte, key string, value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+	// JSON strings and arrays are also valid in YAML and TOML.
+	v := strings.TrimSpace(buf.String())
+	keys := strings.Split(key, ".")
+
+	switch {
+	case bytes.HasPrefix(content, []byte("---")):
+		return setLineValue(content, "---", keys, v, setYAMLValue)
+	case bytes.HasPrefix(content, []byte("+++")):
+		return setLineValue(content, "+++", keys, v, setTOMLValue)
+	case bytes.HasPrefix(content, []byte("{")):
+		return setJSONValue(content, 0, keys, []byte(v))
+	}
+	return nil, ErrUnsupportedFrontMatter
+}
+
+type lineSetter func(lines []string, nl string, keys []string, value string) ([]string, error)
+
+// setLineValue updates the lines between the delimiters with the setter.
+func setLineValue(content []byte, delim string, keys []string, value string, set lineSetter) ([]byte, error) {
+	lines := strings.SplitAfter(string(content), "\n")
+	nl := "\n"
+	if strings.HasSuffix(lines[0], "\r\n") {
+		nl = "\r\n"
+	}
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], "\r\n") == delim {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, fmt.Errorf("front matter is not closed by %q", delim)
+	}
+	fm, err := set(append([]string(nil), lines[1:end]...), nl, keys, value)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	b.WriteString(lines[0])
+	for _, l := range fm {
+		b.WriteString(l)
+	}
+	for _, l := range lines[end:] {
+		b.WriteString(l)
+	}
+	return []byte(b.String()), nil
+}
+
+func setYAMLValue(lines []string, nl string, keys []string, value string) ([]string, error) {
+	return setYAMLBlock(lines, 0, len(lines), 0, nl, keys, value)
+}
+
+// setYAMLBlock sets the value to the keys in the mapping of lines[start:end] which is indented by the indent.
+func setYAMLBlock(lines []string, start, end, indent int, nl string, keys []string, value string) ([]string, error) {
+	l := findYAMLKey(lines, start, end, indent, keys[0])
+	if l < 0 {
+		var ins []string
+		for i, k := range keys {
+			s := strings.Repeat(" ", indent+2*i) + k + ":"
+			if i == len(keys)-1 {
+				s += " " + value
+			}
+			ins = append(ins, s+nl)
+		}
+		return insertLines(lines, trimBlankLines(lines, start, end), ins), nil
+	}
+
+	colon := strings.Index(lines[l], ":")
+	blockEnd := l + 1
+	// a flow collection (e.g. "[" at the end of the line) continues until its brackets are
+	// balanced, and its closing bracket may be at the indent of the key
+	if rest := strings.TrimSpace(lines[l][colon+1:]); strings.HasPrefix(rest, "[") || strings.HasPrefix(rest, "{") {
+		for depth := flowDepth(rest); depth > 0 && blockEnd < end; blockEnd++ {
+			depth += flowDepth(lines[blockEnd])
+		}
+	}
+	for blockEnd < end && isYAMLChild(lines[blockEnd], indent) {
+		blockEnd++
+	}
+	blockEnd = trimBlankLines(lines, l+1, blockEnd)
+
+	if len(keys) == 1 {
+		lines[l] = lines[l][:colon+1] + " " + value + nl
+		return append(lines[:l+1], lines[blockEnd:]...), nil
+	}
+	if rest := strings.TrimSpace(lines[l][colon+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+		return nil, fmt.Errorf("%q must be a block mapping to set %q", keys[0], keys[1])
+	}
+	childIndent := indent + 2
+	for i := l + 1; i < blockEnd; i++ {
+		if !isBlankLine(lines[i]) {
+			childIndent = indentOf(lines[i])
+			break
+		}
+	}
+	return setYAMLBlock(lines, l+1, blockEnd, childIndent, nl, keys[1:], value)
+}
+
+// findYAMLKey returns the index of the line which has the key at the indent, or -1 if it is not found.
+func findYAMLKey(lines []string, start, end, indent int, key string) int {
+	for i := start; i < end; i++ {
+		if indentOf(lines[i]) != indent {
+			continue
+		}
+		s := strings.TrimSpace(lines[i])
+		for _, k := range []string{key, `"` + key + `"`, "'" + key + "'"} {
+			if rest, ok := strings.CutPrefix(s, k); ok && strings.HasPrefix(strings.TrimLeft(rest, " "), ":") {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isYAMLChild reports whether the line is a part of the value of the key at the indent.
+// Items of a block sequence may have the same indent as the key.
+func isYAMLChild(line string, indent int) bool {
+	if isBlankLine(line) {
+		return true
+	}
+	i := indentOf(line)
+	return i > indent || (i == indent && strings.HasPrefix(strings.TrimSpace(line), "-"))
+}
+
+func setTOMLValue(lines []string, nl string, keys []string, value string) ([]string, error) {
+	inString := tomlMultiLineStrings(lines)
+	isHeader := func(i int) bool {
+		return !inString[i] && isTOMLHeader(lines[i])
+	}
+	// the table of the key cannot be replaced with the value
+	full := strings.Join(keys, ".")
+	for i, l := range lines {
+		if name := tomlHeaderName(l); isHeader(i) && (name == full || strings.HasPrefix(name, full+".")) {
+			return nil, fmt.Errorf("%q is a table, so it cannot be set to the value", full)
+		}
+	}
+
+	// the top-level keys end at the first table header
+	topEnd := len(lines)
+	for i := range lines {
+		if isHeader(i) {
+			topEnd = i
+			break
+		}
+	}
+	// look for the table of the longest parent key first
+	for i := len(keys) - 1; i > 0; i-- {
+		name := strings.Join(keys[:i], ".")
+		for h, l := range lines {
+			if !isHeader(h) || tomlHeaderName(l) != name {
+				continue
+			}
+			end := len(lines)
+			for j := h + 1; j < len(lines); j++ {
+				if isHeader(j) {
+					end = j
+					break
+				}
+			}
+			return setTOMLKey(lines, inString, h+1, end, nl, strings.Join(keys[i:], "."), value), nil
+		}
+	}
+	return setTOMLKey(lines, inString, 0, topEnd, nl, full, value), nil
+}
+
+// setTOMLKey sets the value to the (dotted) key in lines[start:end]. The lines in the multi-line
+// strings are not the keys.
+func setTOMLKey(lines []string, inString []bool, start, end int, nl, key, value string) []string {
+	for i := start; i < end; i++ {
+		if inString[i] {
+			continue
+		}
+		s := strings.TrimSpace(lines[i])
+		rest, ok := strings.CutPrefix(s, key)
+		if !ok || !strings.HasPrefix(strings.TrimLeft(rest, " \t"), "=") {
+			continue
+		}
+		// the value (e.g. multi-line array or string) ends where the brackets are balanced
+		// and the string is closed
+		valueEnd := i + 1
+		depth := flowDepth(s[strings.Index(s, "=")+1:])
+		for valueEnd < end && (depth > 0 || inString[valueEnd]) {
+			if !inString[valueEnd] {
+				depth += flowDepth(lines[valueEnd])
+			}
+			valueEnd++
+		}
+		eq := strings.Index(lines[i], "=")
+		lines[i] = lines[i][:eq+1] + " " + value + nl
+		return append(lines[:i+1], lines[valueEnd:]...)
+	}
+	return insertLines(lines, trimBlankLines(lines, start, end), []string{key + " = " + value + nl})
+}
+
+// tomlMultiLineStrings returns whether each line starts in a multi-line string (""" or ”').
+func tomlMultiLineStrings(lines []string) []bool {
+	inString := make([]bool, len(lines)+1)
+	var open string
+	for i, l := range lines {
+		inString[i] = open != ""
+		for {
+			if open == "" {
+				j := strings.Index(l, `"""`)
+				if k := strings.Index(l, "'''"); k >= 0 && (j < 0 || k < j) {
+					j = k
+				}
+				if j < 0 || strings.Contains(l[:j], "#") {
+					break
+				}
+				open, l = l[j:j+3], l[j+3:]
+				continue
+			}
+			j := strings.Index(l, open)
+			if j < 0 {
+				break
+			}
+			open, l = "", l[j+3:]
+		}
+	}
+	return inString
+}
+
+func isTOMLHeader(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "[")
+}
+
+func tomlHeaderName(line string) string {
+	// the header of the array of tables is enclosed in the double brackets
+	s := strings.TrimLeft(strings.TrimSpace(line), "[")
+	if i := strings.Index(s, "]"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.ReplaceAll(s, " ", "")
+}
+
+// flowDepth returns the number of the unclosed brackets of the TOML or YAML flow collection in the
+// line. Brackets in strings and comments are ignored.
+func flowDepth(s string) int {
+	depth := 0
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return depth
+		case r == '[' || r == '{':
+			depth++
+		case r == ']' || r == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+// setJSONValue sets the value to the keys in the JSON object which starts at the offset of the content.
+func setJSONValue(content []byte, offset int, keys []string, value []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(content[offset:]))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	// indentation of the first member is used for the new member
+	sep := []byte(" ")
+	rest := content[offset+1:]
+	if ws := rest[:len(rest)-len(bytes.TrimLeft(rest, " \t\r\n"))]; bytes.Contains(ws, []byte("\n")) {
+		sep = ws
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		before := offset + int(dec.InputOffset())
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		if t.(string) != keys[0] {
+			continue
+		}
+		start := before + bytes.IndexAny(content[before:], "{[\"-0123456789tfn")
+		if len(keys) == 1 {
+			return replaceBytes(content, start, offset+int(dec.InputOffset()), value), nil
+		}
+		if raw[0] != '{' {
+			return nil, fmt.Errorf("%q must be an object to set %q", keys[0], keys[1])
+		}
+		return setJSONValue(content, start, keys[1:], value)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	closing := offset + int(dec.InputOffset()) - 1
+	last := len(bytes.TrimRight(content[:closing], " \t\r\n"))
+
+	v := value
+	for i := len(keys) - 1; i > 0; i-- {
+		v = []byte(fmt.Sprintf("{%q: %s}", keys[i], v))
+	}
+	member := fmt.Sprintf("%s%q: %s", sep, keys[0], v)
+	if content[last-1] != '{' {
+		member = "," + member
+	}
+	return replaceBytes(content, last, last, []byte(member)), nil
+}
+
+func replaceBytes(b []byte, start, end int, s []byte) []byte {
+	out := make([]byte, 0, len(b)-(end-start)+len(s))
+	out = append(out, b[:start]...)
+	out = append(out, s...)
+	return append(out, b[end:]...)
+}
+
+func insertLines(lines []string, at int, ins []string) []string {
+	out := make([]string, 0, len(lines)+len(ins))
+	out = append(out, lines[:at]...)
+	out = append(out, ins...)
+	return append(out, lines[at:]...)
+}
+
+// trimBlankLines returns the end of lines[start:end] without the trailing blank lines.
+func trimBlankLines(lines []string, start, end int) int {
+	for end > start && isBlankLine(lines[end-1]) {
+		end--
+	}
+	return end
+}
+
+func isBlankLine(line string) bool {
+	s := strings.TrimSpace(line)
+	return s == "" || strings.HasPrefix(s, "#")
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
@@ -0,0 +1,167 @@
+package hugo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gohugoio/hugo/parser/pageparser"
+)
+
+func TestSetFrontMatterValue(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		input  string
+		key    string
+		value  interface{}
+		expect string
+	}{
+		{
+			desc:   "Add YAML key",
+			input:  "---\ntitle: Hello\ntags: [go]\n\n---\nbody: text\n",
+			key:    "images",
+			value:  []string{"/og/hello.png"},
+			expect: "---\ntitle: Hello\ntags: [go]\nimages: [\"/og/hello.png\"]\n\n---\nbody: text\n",
+		},
+		{
+			desc:   "Replace YAML block sequence",
+			input:  "---\nimages:\n- /old.png\n- /cover.png\ntitle: Hello\n---\n",
+			key:    "images",
+			value:  []string{"/og/hello.png", "/cover.png"},
+			expect: "---\nimages: [\"/og/hello.png\",\"/cover.png\"]\ntitle: Hello\n---\n",
+		},
+		{
+			desc:   "Replace nested YAML key",
+			input:  "---\nparams:\n    og: old.png\n    toc: true\ntitle: Hello\n---\n",
+			key:    "params.og",
+			value:  "/og/hello.png",
+			expect: "---\nparams:\n    og: \"/og/hello.png\"\n    toc: true\ntitle: Hello\n---\n",
+		},
+		{
+			desc:   "Add nested YAML key to the existing mapping",
+			input:  "---\nparams:\n  toc: true\ntitle: Hello\n---\n",
+			key:    "params.og",
+			value:  "/og/hello.png",
+			expect: "---\nparams:\n  toc: true\n  og: \"/og/hello.png\"\ntitle: Hello\n---\n",
+		},
+		{
+			desc:   "Add nested YAML key with CRLF",
+			input:  "---\r\ntitle: Hello\r\n---\r\nbody\r\n",
+			key:    "params.og",
+			value:  "/og/hello.png",
+			expect: "---\r\ntitle: Hello\r\nparams:\r\n  og: \"/og/hello.png\"\r\n---\r\nbody\r\n",
+		},
+		{
+			desc:   "Replace YAML multi-line flow sequence",
+			input:  "---\nimages: [\n  \"x.png\"\n]\ntags: [a]\n---\n",
+			key:    "images",
+			value:  []string{"/og/a.png", "x.png"},
+			expect: "---\nimages: [\"/og/a.png\",\"x.png\"]\ntags: [a]\n---\n",
+		},
+		{
+			desc:   "Add TOML key before tables",
+			input:  "+++\ntitle = \"Hello\"\n\n[params]\ntoc = true\n+++\n",
+			key:    "featured_image",
+			value:  "/og/hello.png",
+			expect: "+++\ntitle = \"Hello\"\nfeatured_image = \"/og/hello.png\"\n\n[params]\ntoc = true\n+++\n",
+		},
+		{
+			desc:   "Replace TOML multi-line array",
+			input:  "+++\nimages = [\n  \"/old.png\", # [generated]\n]\ntitle = \"Hello\"\n+++\n",
+			key:    "images",
+			value:  []string{"/og/hello.png"},
+			expect: "+++\nimages = [\"/og/hello.png\"]\ntitle = \"Hello\"\n+++\n",
+		},
+		{
+			desc:   "Skip TOML multi-line strings",
+			input:  "+++\ndescription = \"\"\"\nimages = 1\n[params]\n\"\"\"\nimages = [\"/old.png\"]\n+++\n",
+			key:    "images",
+			value:  []string{"/og/a.png"},
+			expect: "+++\ndescription = \"\"\"\nimages = 1\n[params]\n\"\"\"\nimages = [\"/og/a.png\"]\n+++\n",
+		},
+		{
+			desc:   "Add TOML key after multi-line string",
+			input:  "+++\ndescription = '''\nimages = 1\n'''\n+++\n",
+			key:    "images",
+			value:  []string{"/og/a.png"},
+			expect: "+++\ndescription = '''\nimages = 1\n'''\nimages = [\"/og/a.png\"]\n+++\n",
+		},
+		{
+			desc:   "Add TOML key to the table",
+			input:  "+++\ntitle = \"Hello\"\n[params]\ntoc = true\n[[resources]]\nsrc = \"a.png\"\n+++\n",
+			key:    "params.og",
+			value:  "/og/hello.png",
+			expect: "+++\ntitle = \"Hello\"\n[params]\ntoc = true\nog = \"/og/hello.png\"\n[[resources]]\nsrc = \"a.png\"\n+++\n",
+		},
+		{
+			desc:   "Add TOML dotted key",
+			input:  "+++\ntitle = \"Hello\"\n+++\n",
+			key:    "params.og",
+			value:  "/og/hello.png",
+			expect: "+++\ntitle = \"Hello\"\nparams.og = \"/og/hello.png\"\n+++\n",
+		},
+		{
+			desc:   "Replace JSON key",
+			input:  "{\n  \"images\": [\"/old.png\"],\n  \"title\": \"Hello\"\n}\nbody\n",
+			key:    "images",
+			value:  []string{"/og/hello.png"},
+			expect: "{\n  \"images\": [\"/og/hello.png\"],\n  \"title\": \"Hello\"\n}\nbody\n",
+		},
+		{
+			desc:   "Add JSON key",
+			input:  "{\n  \"title\": \"Hello\"\n}\nbody\n",
+			key:    "featured_image",
+			value:  "/og/hello.png",
+			expect: "{\n  \"title\": \"Hello\",\n  \"featured_image\": \"/og/hello.png\"\n}\nbody\n",
+		},
+		{
+			desc:   "Add nested JSON key",
+			input:  "{\"title\": \"Hello\", \"params\": {\"toc\": true}}\nbody\n",
+			key:    "params.og",
+			value:  "/og/hello.png",
+			expect: "{\"title\": \"Hello\", \"params\": {\"toc\": true, \"og\": \"/og/hello.png\"}}\nbody\n",
+		},
+		{
+			desc:   "Add nested JSON object",
+			input:  "{}\nbody\n",
+			key:    "params.og",
+			value:  "/og/hello.png",
+			expect: "{ \"params\": {\"og\": \"/og/hello.png\"}}\nbody\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := SetFrontMatterValue([]byte(tc.input), tc.key, tc.value)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tc.expect {
+				t.Fatalf("want %q, but got %q", tc.expect, got)
+			}
+
+			// the updated front matter must be parsed as the value
+			cfm, err := pageparser.ParseFrontMatterAndContent(strings.NewReader(string(got)))
+			if err != nil {
+				t.Fatalf("failed to parse the updated front matter: %v", err)
+			}
+			if v, _ := lookup(cfm.FrontMatter, tc.key); v == nil {
+				t.Fatalf("%q is not set", tc.key)
+			}
+		})
+	}
+}
+
+func TestSetFrontMatterValueErrors(t *testing.T) {
+	if _, err := SetFrontMatterValue([]byte("#+TITLE: Hello\n"), "images", "/a.png"); !errors.Is(err, ErrUnsupportedFrontMatter) {
+		t.Errorf("want ErrUnsupportedFrontMatter, but got %v", err)
+	}
+	if _, err := SetFrontMatterValue([]byte("---\ntitle: Hello\n"), "images", "/a.png"); err == nil {
+		t.Error("want error for unclosed front matter")
+	}
+	if _, err := SetFrontMatterValue([]byte("---\nparams: {toc: true}\n---\n"), "params.og", "/a.png"); err == nil {
+		t.Error("want error for flow mapping")
+	}
+	if _, err := SetFrontMatterValue([]byte("+++\n[params]\ntoc = true\n[params.og]\nurl = \"a\"\n+++\n"), "params.og", "/a.png"); err == nil {
+		t.Error("want error for the existing table")
+	}
+}
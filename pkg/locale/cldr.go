@@ -0,0 +1,390 @@
+package locale
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Calendars of the dates.
+const (
+	Gregorian = "gregorian"
+	Japanese  = "japanese"
+)
+
+// Date styles which are replaced with the CLDR pattern of the language.
+const (
+	StyleShort  = "short"
+	StyleMedium = "medium"
+	StyleLong   = "long"
+	StyleFull   = "full"
+)
+
+// styles are the CLDR date patterns of the Gregorian calendar by the styles.
+var styles = map[string]map[string]string{
+	"en": {StyleShort: "M/d/yy", StyleMedium: "MMM d, y", StyleLong: "MMMM d, y", StyleFull: "EEEE, MMMM d, y"},
+	"ja": {StyleShort: "y/MM/dd", StyleMedium: "y/MM/dd", StyleLong: "y年M月d日", StyleFull: "y年M月d日EEEE"},
+	"zh": {StyleShort: "y/M/d", StyleMedium: "y年M月d日", StyleLong: "y年M月d日", StyleFull: "y年M月d日EEEE"},
+	"ko": {StyleShort: "yy. M. d.", StyleMedium: "y. M. d.", StyleLong: "y년 MMMM d일", StyleFull: "y년 MMMM d일 EEEE"},
+	"de": {StyleShort: "dd.MM.yy", StyleMedium: "dd.MM.y", StyleLong: "d. MMMM y", StyleFull: "EEEE, d. MMMM y"},
+	"fr": {StyleShort: "dd/MM/y", StyleMedium: "d MMM y", StyleLong: "d MMMM y", StyleFull: "EEEE d MMMM y"},
+	"es": {StyleShort: "d/M/yy", StyleMedium: "d MMM y", StyleLong: "d 'de' MMMM 'de' y", StyleFull: "EEEE, d 'de' MMMM 'de' y"},
+}
+
+// japaneseStyles are the CLDR date patterns of the Japanese calendar in Japanese. The other
+// languages use the Gregorian patterns with the era.
+var japaneseStyles = map[string]string{
+	StyleShort: "GGGGGy/M/d", StyleMedium: "Gy年M月d日", StyleLong: "Gy年M月d日", StyleFull: "Gy年M月d日EEEE",
+}
+
+// eraNames are the abbreviated and wide names of an era.
+type eraNames struct {
+	abbr, wide string
+}
+
+// gregorianEras are the names of the common era.
+var gregorianEras = map[string]eraNames{
+	"en": {"AD", "Anno Domini"},
+	"ja": {"西暦", "西暦"},
+	"zh": {"公元", "公元"},
+	"ko": {"AD", "서기"},
+	"de": {"n. Chr.", "n. Chr."},
+	"fr": {"ap. J.-C.", "après Jésus-Christ"},
+	"es": {"d. C.", "después de Cristo"},
+}
+
+// japaneseEra is an era of the Japanese calendar.
+type japaneseEra struct {
+	start  time.Time
+	narrow string
+	names  map[string]string
+}
+
+// japaneseEras are the modern eras of the Japanese calendar in descending order.
+var japaneseEras = []japaneseEra{
+	{start: time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC), narrow: "R",
+		names: map[string]string{"en": "Reiwa", "ja": "令和", "zh": "令和", "ko": "레이와"}},
+	{start: time.Date(1989, 1, 8, 0, 0, 0, 0, time.UTC), narrow: "H",
+		names: map[string]string{"en": "Heisei", "ja": "平成", "zh": "平成", "ko": "헤이세이"}},
+	{start: time.Date(1926, 12, 25, 0, 0, 0, 0, time.UTC), narrow: "S",
+		names: map[string]string{"en": "Shōwa", "ja": "昭和", "zh": "昭和", "ko": "쇼와"}},
+	{start: time.Date(1912, 7, 30, 0, 0, 0, 0, time.UTC), narrow: "T",
+		names: map[string]string{"en": "Taishō", "ja": "大正", "zh": "大正", "ko": "다이쇼"}},
+	{start: time.Date(1868, 9, 8, 0, 0, 0, 0, time.UTC), narrow: "M",
+		names: map[string]string{"en": "Meiji", "ja": "明治", "zh": "明治", "ko": "메이지"}},
+}
+
+// dayPeriods are the names of AM and PM.
+var dayPeriods = map[string][2]string{
+	"en": {"AM", "PM"},
+	"ja": {"午前", "午後"},
+	"zh": {"上午", "下午"},
+	"ko": {"오전", "오후"},
+	"de": {"AM", "PM"},
+	"fr": {"AM", "PM"},
+	"es": {"a. m.", "p. m."},
+}
+
+// updatedFormats are the formats of the text which shows the updated date.
+var updatedFormats = map[string]string{
+	"en": "Updated %s",
+	"ja": "%s 更新",
+	"zh": "更新于 %s",
+	"ko": "%s 업데이트",
+	"de": "Aktualisiert am %s",
+	"fr": "Mis à jour le %s",
+	"es": "Actualizado el %s",
+}
+
+// plural is the formats of the number whose plural category is one or other.
+type plural struct {
+	one, other string
+}
+
+// format formats the number with the plural format.
+func (p plural) format(n int) string {
+	if n == 1 {
+		return fmt.Sprintf(p.one, n)
+	}
+	return fmt.Sprintf(p.other, n)
+}
+
+// relativeNames are the CLDR names of the relative dates. The past and future formats are by the
+// units of the days, months, and years.
+type relativeNames struct {
+	today, yesterday, tomorrow string
+	past, future               [3]plural
+}
+
+// relativeDates are the names of the relative dates by the languages.
+var relativeDates = map[string]relativeNames{
+	"en": {
+		today: "today", yesterday: "yesterday", tomorrow: "tomorrow",
+		past:   [3]plural{{"%d day ago", "%d days ago"}, {"%d month ago", "%d months ago"}, {"%d year ago", "%d years ago"}},
+		future: [3]plural{{"in %d day", "in %d days"}, {"in %d month", "in %d months"}, {"in %d year", "in %d years"}},
+	},
+	"ja": {
+		today: "今日", yesterday: "昨日", tomorrow: "明日",
+		past:   [3]plural{{"%d日前", "%d日前"}, {"%dか月前", "%dか月前"}, {"%d年前", "%d年前"}},
+		future: [3]plural{{"%d日後", "%d日後"}, {"%dか月後", "%dか月後"}, {"%d年後", "%d年後"}},
+	},
+	"zh": {
+		today: "今天", yesterday: "昨天", tomorrow: "明天",
+		past:   [3]plural{{"%d天前", "%d天前"}, {"%d个月前", "%d个月前"}, {"%d年前", "%d年前"}},
+		future: [3]plural{{"%d天后", "%d天后"}, {"%d个月后", "%d个月后"}, {"%d年后", "%d年后"}},
+	},
+	"ko": {
+		today: "오늘", yesterday: "어제", tomorrow: "내일",
+		past:   [3]plural{{"%d일 전", "%d일 전"}, {"%d개월 전", "%d개월 전"}, {"%d년 전", "%d년 전"}},
+		future: [3]plural{{"%d일 후", "%d일 후"}, {"%d개월 후", "%d개월 후"}, {"%d년 후", "%d년 후"}},
+	},
+	"de": {
+		today: "heute", yesterday: "gestern", tomorrow: "morgen",
+		past:   [3]plural{{"vor %d Tag", "vor %d Tagen"}, {"vor %d Monat", "vor %d Monaten"}, {"vor %d Jahr", "vor %d Jahren"}},
+		future: [3]plural{{"in %d Tag", "in %d Tagen"}, {"in %d Monat", "in %d Monaten"}, {"in %d Jahr", "in %d Jahren"}},
+	},
+	"fr": {
+		today: "aujourd’hui", yesterday: "hier", tomorrow: "demain",
+		past:   [3]plural{{"il y a %d jour", "il y a %d jours"}, {"il y a %d mois", "il y a %d mois"}, {"il y a %d an", "il y a %d ans"}},
+		future: [3]plural{{"dans %d jour", "dans %d jours"}, {"dans %d mois", "dans %d mois"}, {"dans %d an", "dans %d ans"}},
+	},
+	"es": {
+		today: "hoy", yesterday: "ayer", tomorrow: "mañana",
+		past:   [3]plural{{"hace %d día", "hace %d días"}, {"hace %d mes", "hace %d meses"}, {"hace %d año", "hace %d años"}},
+		future: [3]plural{{"dentro de %d día", "dentro de %d días"}, {"dentro de %d mes", "dentro de %d meses"}, {"dentro de %d año", "dentro de %d años"}},
+	},
+}
+
+// supported returns the base language if its CLDR data is available, or English.
+func supported(lang string) string {
+	if base := Base(lang); styles[base] != nil {
+		return base
+	}
+	return "en"
+}
+
+// FormatDate formats the time with the CLDR date pattern (e.g. "EEEE, MMMM d, y") or the style
+// (short, medium, long, or full) of the language. The calendar is gregorian or japanese, and the
+// empty calendar is gregorian. The language which is not supported is formatted in English.
+//
+// The pattern supports the era (G), year (y), month (M and L), day (d), weekday (E), AM or PM (a),
+// hour (h and H), minute (m), and second (s) fields, and the text quoted with ' is written as is.
+func FormatDate(lang, calendar, pattern string, t time.Time) (string, error) {
+	lang = supported(lang)
+	switch calendar {
+	case "", Gregorian:
+		calendar = Gregorian
+	case Japanese:
+	default:
+		return "", fmt.Errorf("unsupported calendar %q: supported calendars are %s and %s", calendar, Gregorian, Japanese)
+	}
+	if p, ok := styles[lang][pattern]; ok {
+		if calendar == Japanese {
+			p = japaneseStyle(lang, pattern)
+		}
+		pattern = p
+	}
+
+	f := dateFormatter{lang: lang, t: t, names: namesOf(lang), year: t.Year(), era: gregorianEras[lang]}
+	if calendar == Japanese {
+		f.setJapaneseEra()
+	}
+	var b strings.Builder
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		switch {
+		case c == '\'':
+			// '' is a quote, and the other quoted text is a literal
+			if i+1 < len(pattern) && pattern[i+1] == '\'' {
+				b.WriteByte('\'')
+				i += 2
+				continue
+			}
+			for i++; i < len(pattern); i++ {
+				if pattern[i] == '\'' {
+					if i+1 < len(pattern) && pattern[i+1] == '\'' {
+						i++
+					} else {
+						break
+					}
+				}
+				b.WriteByte(pattern[i])
+			}
+			i++
+		case 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+			n := 1
+			for i+n < len(pattern) && pattern[i+n] == c {
+				n++
+			}
+			s := f.field(c, n)
+			if c == 'y' && n == 1 && f.isFirstYear() && strings.HasPrefix(pattern[i+n:], "年") {
+				// the first year of the Japanese era is written as "元年"
+				s = "元"
+			}
+			b.WriteString(s)
+			i += n
+		default:
+			_, size := utf8.DecodeRuneInString(pattern[i:])
+			b.WriteString(pattern[i : i+size])
+			i += size
+		}
+	}
+	return b.String(), nil
+}
+
+// japaneseStyle returns the pattern of the style in the Japanese calendar.
+func japaneseStyle(lang, style string) string {
+	pattern := styles[lang][style]
+	switch lang {
+	case "ja":
+		return japaneseStyles[style]
+	case "zh", "ko":
+		return "G" + pattern
+	}
+	return pattern + " G"
+}
+
+// Updated returns the text which shows the date is updated, such as "Updated Jun 23" in English.
+func Updated(lang, date string) string {
+	return fmt.Sprintf(updatedFormats[supported(lang)], date)
+}
+
+// Relative returns the date relative to the current time in the language, such as "3 days ago" in
+// English. The dates are compared by the days in the location of the date, and the numbers of days
+// are rounded down to the months (30 days) and years (365 days).
+func Relative(lang string, t, now time.Time) string {
+	names := relativeDates[supported(lang)]
+	now = now.In(t.Location())
+	days := int(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).
+		Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)).Hours() / 24)
+	switch days {
+	case 0:
+		return names.today
+	case 1:
+		return names.yesterday
+	case -1:
+		return names.tomorrow
+	}
+	formats := names.past
+	if days < 0 {
+		formats, days = names.future, -days
+	}
+	switch {
+	case days < 30:
+		return formats[0].format(days)
+	case days < 365:
+		return formats[1].format(days / 30)
+	}
+	return formats[2].format(days / 365)
+}
+
+type dateFormatter struct {
+	lang  string
+	t     time.Time
+	names *names
+	// year is the year of the era.
+	year int
+	era  eraNames
+	// narrowEra is the narrow name of the Japanese era.
+	narrowEra string
+	japanese  bool
+}
+
+// setJapaneseEra sets the era of the Japanese calendar. The dates before Meiji are in the
+// Gregorian calendar.
+func (f *dateFormatter) setJapaneseEra() {
+	date := time.Date(f.t.Year(), f.t.Month(), f.t.Day(), 0, 0, 0, 0, time.UTC)
+	for _, e := range japaneseEras {
+		if date.Before(e.start) {
+			continue
+		}
+		name, ok := e.names[f.lang]
+		if !ok {
+			name = e.names["en"]
+		}
+		f.era = eraNames{abbr: name, wide: name}
+		f.narrowEra = e.narrow
+		f.year = f.t.Year() - e.start.Year() + 1
+		f.japanese = true
+		return
+	}
+}
+
+func (f *dateFormatter) isFirstYear() bool {
+	return f.japanese && f.year == 1
+}
+
+// field formats the field of the pattern letter repeated n times.
+func (f *dateFormatter) field(c byte, n int) string {
+	t := f.t
+	switch c {
+	case 'G':
+		switch {
+		case n == 4:
+			return f.era.wide
+		case n == 5 && f.narrowEra != "":
+			return f.narrowEra
+		}
+		return f.era.abbr
+	case 'y':
+		if n == 2 {
+			return pad(f.year%100, 2)
+		}
+		return pad(f.year, n)
+	case 'M', 'L':
+		switch {
+		case n == 3:
+			return f.names.shortMonths[t.Month()-1]
+		case n == 4:
+			return f.names.months[t.Month()-1]
+		case n >= 5:
+			return firstRune(f.names.months[t.Month()-1])
+		}
+		return pad(int(t.Month()), n)
+	case 'd':
+		return pad(t.Day(), n)
+	case 'E':
+		switch {
+		case n == 4:
+			return f.names.days[t.Weekday()]
+		case n >= 5:
+			return firstRune(f.names.days[t.Weekday()])
+		}
+		return f.names.shortDays[t.Weekday()]
+	case 'a':
+		if t.Hour() < 12 {
+			return dayPeriods[f.lang][0]
+		}
+		return dayPeriods[f.lang][1]
+	case 'h':
+		h := t.Hour() % 12
+		if h == 0 {
+			h = 12
+		}
+		return pad(h, n)
+	case 'H':
+		return pad(t.Hour(), n)
+	case 'm':
+		return pad(t.Minute(), n)
+	case 's':
+		return pad(t.Second(), n)
+	}
+	// the unknown letters are written as is
+	return strings.Repeat(string(c), n)
+}
+
+// pad formats the number with the leading zeros up to the width.
+func pad(v, width int) string {
+	s := strconv.Itoa(v)
+	if len(s) < width {
+		s = strings.Repeat("0", width-len(s)) + s
+	}
+	return s
+}
+
+func firstRune(s string) string {
+	_, size := utf8.DecodeRuneInString(s)
+	return s[:size]
+}
@@ -0,0 +1,138 @@
+// Package locale formats dates for the languages of the contents.
+package locale
+
+import (
+	"strings"
+	"time"
+)
+
+// names are the localized names of the months and weekdays.
+type names struct {
+	months      [12]string
+	shortMonths [12]string
+	days        [7]string
+	shortDays   [7]string
+}
+
+var (
+	en = &names{
+		months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		days:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		shortDays:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	}
+	ja = &names{
+		months:      [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		shortMonths: [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		days:        [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		shortDays:   [7]string{"日", "月", "火", "水", "木", "金", "土"},
+	}
+	zh = &names{
+		months:      [12]string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+		shortMonths: [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		days:        [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+		shortDays:   [7]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"},
+	}
+	ko = &names{
+		months:      [12]string{"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"},
+		shortMonths: [12]string{"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"},
+		days:        [7]string{"일요일", "월요일", "화요일", "수요일", "목요일", "금요일", "토요일"},
+		shortDays:   [7]string{"일", "월", "화", "수", "목", "금", "토"},
+	}
+	de = &names{
+		months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		shortDays:   [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+	}
+	fr = &names{
+		months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		shortDays:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+	}
+	es = &names{
+		months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		shortDays:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+	}
+)
+
+// localNames are the names of the supported languages except English, whose names are of Go.
+var localNames = map[string]*names{
+	"ja": ja,
+	"zh": zh,
+	"ko": ko,
+	"de": de,
+	"fr": fr,
+	"es": es,
+}
+
+// Base returns the base language of the language tag, such as "ja" of "ja-JP".
+func Base(lang string) string {
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// IsSupported returns true if the dates are localized in the language, whose base language is
+// English or has the localized names.
+func IsSupported(lang string) bool {
+	base := Base(lang)
+	_, ok := localNames[base]
+	return ok || base == "en"
+}
+
+// namesOf returns the names of the language, or the English names if it is not supported.
+func namesOf(lang string) *names {
+	if n, ok := localNames[Base(lang)]; ok {
+		return n
+	}
+	return en
+}
+
+// FormatTime formats the time with the Go layout like time.Format, and the names of the month
+// and weekday (January, Jan, Monday, and Mon) are localized in the language. As time.Format does,
+// Jan and Mon followed by a lower-case letter such as "Janet" are not the names.
+// The language which is not supported is formatted in English.
+func FormatTime(lang, layout string, t time.Time) string {
+	n, ok := localNames[Base(lang)]
+	if !ok {
+		return t.Format(layout)
+	}
+
+	var b strings.Builder
+	start := 0
+	for i := 0; i < len(layout); {
+		var name string
+		var size int
+		switch s := layout[i:]; {
+		case strings.HasPrefix(s, "January"):
+			name, size = n.months[t.Month()-1], len("January")
+		case strings.HasPrefix(s, "Jan") && !startsWithLowerCase(s[len("Jan"):]):
+			name, size = n.shortMonths[t.Month()-1], len("Jan")
+		case strings.HasPrefix(s, "Monday"):
+			name, size = n.days[t.Weekday()], len("Monday")
+		case strings.HasPrefix(s, "Mon") && !startsWithLowerCase(s[len("Mon"):]):
+			name, size = n.shortDays[t.Weekday()], len("Mon")
+		default:
+			i++
+			continue
+		}
+		// the other parts of the layout are formatted by Go
+		b.WriteString(t.Format(layout[start:i]))
+		b.WriteString(name)
+		i += size
+		start = i
+	}
+	b.WriteString(t.Format(layout[start:]))
+	return b.String()
+}
+
+// startsWithLowerCase returns true if the string starts with a lower-case ASCII letter as time.Format checks.
+func startsWithLowerCase(s string) bool {
+	return s != "" && 'a' <= s[0] && s[0] <= 'z'
+}
@@ -0,0 +1,151 @@
+package locale
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTime(t *testing.T) {
+	// Tuesday
+	date := time.Date(2020, 6, 23, 8, 29, 14, 0, time.UTC)
+
+	testCases := []struct {
+		lang   string
+		layout string
+		expect string
+	}{
+		{lang: "", layout: "Jan 2", expect: "Jun 23"},
+		{lang: "en-us", layout: "Monday, January 2", expect: "Tuesday, June 23"},
+		{lang: "it", layout: "Jan 2", expect: "Jun 23"},
+		{lang: "ja", layout: "Jan2日(Mon)", expect: "6月23日(火)"},
+		{lang: "ja-JP", layout: "2006年1月2日 15:04", expect: "2020年6月23日 08:29"},
+		{lang: "de", layout: "Monday, 2. January 2006", expect: "Dienstag, 23. Juni 2020"},
+		{lang: "fr", layout: "Mon 2 Jan 2006", expect: "mar. 23 juin 2020"},
+		{lang: "es", layout: "2 de January", expect: "23 de junio"},
+		{lang: "zh-cn", layout: "Monday", expect: "星期二"},
+		{lang: "ko", layout: "Jan 2일", expect: "6월 23일"},
+		{lang: "de", layout: "MST", expect: "UTC"},
+		{lang: "de", layout: "Janet, Monaco, Jan", expect: "Janet, Monaco, Juni"},
+		{lang: "ja", layout: "Monday Mon.", expect: "火曜日 火."},
+	}
+	for _, tc := range testCases {
+		if got := FormatTime(tc.lang, tc.layout, date); got != tc.expect {
+			t.Errorf("%s %q: want %q, but got %q", tc.lang, tc.layout, tc.expect, got)
+		}
+	}
+}
+
+func TestBase(t *testing.T) {
+	for lang, expect := range map[string]string{"ja": "ja", "ja-JP": "ja", "zh_Hant": "zh", "": ""} {
+		if got := Base(lang); got != expect {
+			t.Errorf("%q: want %q, but got %q", lang, expect, got)
+		}
+	}
+}
+
+func TestIsSupported(t *testing.T) {
+	for lang, expect := range map[string]bool{"en-US": true, "ja": true, "zh-cn": true, "js": false, "": false} {
+		if got := IsSupported(lang); got != expect {
+			t.Errorf("%q: want %v, but got %v", lang, expect, got)
+		}
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	// Tuesday
+	date := time.Date(2020, 6, 23, 18, 9, 4, 0, time.UTC)
+
+	testCases := []struct {
+		lang     string
+		calendar string
+		pattern  string
+		expect   string
+	}{
+		{lang: "", pattern: "medium", expect: "Jun 23, 2020"},
+		{lang: "it", pattern: "long", expect: "June 23, 2020"},
+		{lang: "en", pattern: "EEEE, MMM d 'at' h:mm a", expect: "Tuesday, Jun 23 at 6:09 PM"},
+		{lang: "ja", pattern: "full", expect: "2020年6月23日火曜日"},
+		{lang: "ja", pattern: "M月d日(E) HH:mm:ss", expect: "6月23日(火) 18:09:04"},
+		{lang: "zh-CN", pattern: "full", expect: "2020年6月23日星期二"},
+		{lang: "ko", pattern: "long", expect: "2020년 6월 23일"},
+		{lang: "de", pattern: "full", expect: "Dienstag, 23. Juni 2020"},
+		{lang: "fr", pattern: "medium", expect: "23 juin 2020"},
+		{lang: "es", pattern: "long", expect: "23 de junio de 2020"},
+		{lang: "en", pattern: "yy/MM/dd G 'o''clock' ''", expect: "20/06/23 AD o'clock '"},
+		{lang: "ja", calendar: "japanese", pattern: "long", expect: "令和2年6月23日"},
+		{lang: "ja", calendar: "japanese", pattern: "short", expect: "R2/6/23"},
+		{lang: "en", calendar: "japanese", pattern: "long", expect: "June 23, 2 Reiwa"},
+		{lang: "ja", calendar: "gregorian", pattern: "Gy年", expect: "西暦2020年"},
+	}
+	for _, tc := range testCases {
+		got, err := FormatDate(tc.lang, tc.calendar, tc.pattern, date)
+		if err != nil {
+			t.Errorf("%s %q: unexpected error: %v", tc.lang, tc.pattern, err)
+			continue
+		}
+		if got != tc.expect {
+			t.Errorf("%s %q: want %q, but got %q", tc.lang, tc.pattern, tc.expect, got)
+		}
+	}
+}
+
+func TestFormatDateJapaneseEra(t *testing.T) {
+	testCases := []struct {
+		date   time.Time
+		expect string
+	}{
+		{date: time.Date(2019, 4, 30, 0, 0, 0, 0, time.UTC), expect: "平成31年4月30日"},
+		{date: time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC), expect: "令和元年5月1日"},
+		{date: time.Date(1989, 1, 7, 0, 0, 0, 0, time.UTC), expect: "昭和64年1月7日"},
+		{date: time.Date(1850, 1, 1, 0, 0, 0, 0, time.UTC), expect: "西暦1850年1月1日"},
+	}
+	for _, tc := range testCases {
+		if got, _ := FormatDate("ja", Japanese, StyleLong, tc.date); got != tc.expect {
+			t.Errorf("%v: want %q, but got %q", tc.date, tc.expect, got)
+		}
+	}
+
+	if _, err := FormatDate("ja", "buddhist", StyleLong, time.Now()); err == nil {
+		t.Error("want error for unsupported calendar")
+	}
+}
+
+func TestUpdated(t *testing.T) {
+	for lang, expect := range map[string]string{"": "Updated Jun 23", "ja-JP": "Jun 23 更新", "de": "Aktualisiert am Jun 23"} {
+		if got := Updated(lang, "Jun 23"); got != expect {
+			t.Errorf("%q: want %q, but got %q", lang, expect, got)
+		}
+	}
+}
+
+func TestRelative(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	testCases := []struct {
+		lang   string
+		t      time.Time
+		expect string
+	}{
+		{lang: "", t: now.Add(-time.Hour), expect: "today"},
+		{lang: "en", t: time.Date(2024, 5, 5, 23, 0, 0, 0, time.UTC), expect: "yesterday"},
+		{lang: "en", t: time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC), expect: "tomorrow"},
+		{lang: "en", t: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC), expect: "3 days ago"},
+		{lang: "en", t: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), expect: "2 months ago"},
+		{lang: "en", t: time.Date(2023, 5, 6, 0, 0, 0, 0, time.UTC), expect: "1 year ago"},
+		{lang: "en", t: time.Date(2024, 5, 9, 0, 0, 0, 0, time.UTC), expect: "in 3 days"},
+		{lang: "ja-JP", t: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC), expect: "3日前"},
+		{lang: "ja", t: time.Date(2024, 5, 7, 0, 0, 0, 0, jst), expect: "明日"},
+		{lang: "zh", t: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), expect: "2年前"},
+		{lang: "ko", t: time.Date(2024, 5, 9, 0, 0, 0, 0, time.UTC), expect: "3일 후"},
+		{lang: "de", t: time.Date(2024, 5, 5, 0, 0, 0, 0, time.UTC), expect: "gestern"},
+		{lang: "de", t: time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC), expect: "vor 1 Monat"},
+		{lang: "fr", t: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC), expect: "il y a 3 jours"},
+		{lang: "es", t: time.Date(2023, 5, 6, 0, 0, 0, 0, time.UTC), expect: "hace 1 año"},
+		{lang: "it", t: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC), expect: "3 days ago"},
+	}
+	for _, tc := range testCases {
+		if got := Relative(tc.lang, tc.t, now); got != tc.expect {
+			t.Errorf("%q %v: want %q, but got %q", tc.lang, tc.t, tc.expect, got)
+		}
+	}
+}
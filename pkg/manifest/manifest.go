@@ -0,0 +1,76 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// DefaultFilename is the default filename of the manifest.
+const DefaultFilename = ".tcardgen-cache.json"
+
+// version is incremented when the digest of inputs is changed.
+const version = 1
+
+// Manifest records the digest of the inputs of each generated card,
+// to skip generating the card whose inputs are not changed.
+type Manifest struct {
+	Version int               `json:"version"`
+	Digests map[string]string `json:"digests"`
+
+	filename string
+	mu       sync.Mutex
+}
+
+// Load loads the manifest file. If the file does not exist or its version is different,
+// an empty manifest is returned.
+func Load(filename string) (*Manifest, error) {
+	m := &Manifest{Version: version, Digests: map[string]string{}, filename: filename}
+	b, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var saved Manifest
+	if err := json.Unmarshal(b, &saved); err != nil {
+		return nil, err
+	}
+	if saved.Version == version && saved.Digests != nil {
+		m.Digests = saved.Digests
+	}
+	return m, nil
+}
+
+// Unchanged returns true if the output exists and was generated from the inputs of the digest.
+func (m *Manifest) Unchanged(output, digest string) bool {
+	m.mu.Lock()
+	d, ok := m.Digests[output]
+	m.mu.Unlock()
+	if !ok || d != digest {
+		return false
+	}
+	_, err := os.Stat(output)
+	return err == nil
+}
+
+// Set records the digest of the inputs of the output.
+func (m *Manifest) Set(output, digest string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Digests[output] = digest
+}
+
+// Save writes the manifest into the file.
+func (m *Manifest) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(m.filename, append(b, '\n'), 0644)
+}
@@ -0,0 +1,39 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, DefaultFilename)
+	out := filepath.Join(dir, "card.png")
+
+	m, err := Load(filename)
+	if err != nil {
+		t.Fatalf("failed to load missing manifest: %v", err)
+	}
+	m.Set(out, "digest1")
+	if m.Unchanged(out, "digest1") {
+		t.Fatal("output which does not exist must not be unchanged")
+	}
+	if err := os.WriteFile(out, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Save(); err != nil {
+		t.Fatalf("failed to save manifest: %v", err)
+	}
+
+	m, err = Load(filename)
+	if err != nil {
+		t.Fatalf("failed to load manifest: %v", err)
+	}
+	if !m.Unchanged(out, "digest1") {
+		t.Fatal("output is changed")
+	}
+	if m.Unchanged(out, "digest2") {
+		t.Fatal("output whose digest is different must not be unchanged")
+	}
+}
@@ -0,0 +1,209 @@
+package tcardgen
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ladicle/tcardgen/pkg/canvas"
+	"github.com/Ladicle/tcardgen/pkg/config"
+	"github.com/Ladicle/tcardgen/pkg/hugo"
+	"github.com/Ladicle/tcardgen/pkg/tmpl"
+)
+
+func (r *Renderer) drawElement(c *canvas.Canvas, e config.Element, fm *hugo.FrontMatter, o *renderOption) error {
+	switch e.Type {
+	case config.ElementText:
+		to := e.Text
+		if !config.IsEnabled(to.Enabled) {
+			return nil
+		}
+		text, err := tmpl.ExecuteLang(fm.Lang, to.Template, fm)
+		if err != nil {
+			return err
+		}
+		return c.DrawTextAtPoint(
+			text,
+			*to.Start,
+			canvas.Align(to.Align),
+			canvas.VerticalAlign(to.VerticalAlign, to.Height),
+			canvas.FgHexColor(to.FgHexColor),
+			canvas.FontFaceFromFFA(r.ffa, to.FontStyle, to.FontSize),
+		)
+	case config.ElementMultiLineText:
+		mto := e.MultiLineText
+		if !config.IsEnabled(mto.Enabled) {
+			return nil
+		}
+		text, err := tmpl.ExecuteLang(fm.Lang, mto.Template, fm)
+		if err != nil {
+			return err
+		}
+		return c.DrawTextAtPoint(
+			text,
+			*mto.Start,
+			canvas.MaxWidth(mto.MaxWidth),
+			canvas.MaxHeight(mto.MaxHeight),
+			canvas.MaxLines(mto.MaxLines),
+			canvas.MinFontSize(mto.MinFontSize),
+			canvas.Ellipsis(*mto.Ellipsis),
+			canvas.Align(mto.Align),
+			canvas.VerticalAlign(mto.VerticalAlign, boxHeight(mto)),
+			canvas.LineSpacing(*mto.LineSpacing),
+			canvas.FgHexColor(mto.FgHexColor),
+			canvas.FontFaceFromFFA(r.ffa, mto.FontStyle, mto.FontSize),
+		)
+	case config.ElementBoxTexts:
+		bto := e.BoxTexts
+		if !config.IsEnabled(bto.Enabled) {
+			return nil
+		}
+		texts, err := sourceTexts(e.Source, fm, bto)
+		if err != nil {
+			return err
+		}
+		return c.DrawBoxTexts(
+			texts,
+			*bto.Start,
+			canvas.FgHexColor(bto.FgHexColor),
+			canvas.BgHexColor(bto.BgHexColor),
+			canvas.BoxPadding(*bto.BoxPadding),
+			canvas.BoxSpacing(*bto.BoxSpacing),
+			canvas.BoxAlign(bto.BoxAlign),
+			canvas.FontFaceFromFFA(r.ffa, bto.FontStyle, bto.FontSize),
+		)
+	case config.ElementImage:
+		imo := e.Image
+		if !config.IsEnabled(imo.Enabled) {
+			return nil
+		}
+		path := imagePath(imo, fm, o)
+		if path == "" {
+			return nil
+		}
+		img, err := r.loadImage(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(o.warn, "WARN: skip the image element: %v\n", err)
+			return nil
+		} else if err != nil {
+			return err
+		}
+		return c.DrawImage(
+			img,
+			*imo.Start,
+			canvas.ImageSize(imo.Width, imo.Height),
+			canvas.ImageFit(imo.Fit),
+			canvas.ImageMask(imo.Mask, imo.Radius),
+			canvas.Opacity(*imo.Opacity),
+		)
+	case config.ElementShape:
+		so := e.Shape
+		if !config.IsEnabled(so.Enabled) {
+			return nil
+		}
+		return c.DrawRect(*so.Start, so.Width, so.Height, canvas.BgHexColor(so.BgHexColor))
+	default:
+		return fmt.Errorf("unknown element type %q", e.Type)
+	}
+}
+
+// imagePath returns the path of the image which is specified in the front matter or the config.
+// The path in the front matter which starts with "/" is the path from the root of the site as Hugo
+// does, so it is in the static directory or the content directory of the site.
+func imagePath(imo *config.ImageOption, fm *hugo.FrontMatter, o *renderOption) string {
+	var p string
+	if imo.Field != "" {
+		p, _ = fm.Param(imo.Field).(string)
+	}
+	switch {
+	case p == "":
+		return imo.Path
+	case imo.Dir != "":
+		return filepath.Join(imo.Dir, p)
+	case strings.HasPrefix(p, "/"):
+		return siteRootPath(p, fm.Site, o.staticDir)
+	case filepath.IsAbs(p):
+		return p
+	default:
+		return filepath.Join(filepath.Dir(o.contentPath), p)
+	}
+}
+
+// siteRootPath returns the file of the path from the root of the site. It is the file in the static
+// directory if it exists in the static directory or the site has no content directory.
+func siteRootPath(p string, site *hugo.SiteConfig, staticDir string) string {
+	static := filepath.Join(staticDir, filepath.FromSlash(p))
+	if site == nil {
+		return static
+	}
+	if _, err := os.Stat(static); err == nil {
+		return static
+	}
+	return filepath.Join(site.Dir, site.ContentDir, filepath.FromSlash(p))
+}
+
+func (r *Renderer) loadImage(path string) (image.Image, error) {
+	if img, ok := r.images.Load(path); ok {
+		return img.(image.Image), nil
+	}
+	img, err := canvas.LoadFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r.images.Store(path, img)
+	return img, nil
+}
+
+// boxHeight returns the height of the box to align the multi-line text vertically.
+func boxHeight(mto *config.MultiLineTextOption) int {
+	if mto.Height != 0 {
+		return mto.Height
+	}
+	return mto.MaxHeight
+}
+
+// boxItem is the data of the box text template.
+type boxItem struct {
+	*hugo.FrontMatter
+	Item string
+}
+
+// sourceTexts returns the texts of the data source to draw them as boxes.
+// When the template is specified, each text is rendered with it.
+func sourceTexts(src config.DataSource, fm *hugo.FrontMatter, bto *config.BoxTextsOption) ([]string, error) {
+	var items []string
+	switch src {
+	case config.SourceTags:
+		items = fm.Tags
+	case config.SourceAuthor:
+		items = []string{fm.Author}
+	case config.SourceCategory:
+		items = []string{fm.Category}
+	default:
+		return nil, fmt.Errorf("%q source cannot be drawn as boxes", src)
+	}
+
+	lim := len(items)
+	if l := bto.Limit; l > 0 && l <= lim {
+		lim = l
+	}
+
+	var texts []string
+	for _, t := range items[:lim] {
+		if *bto.TitleCaseEnabled {
+			t = strings.Title(t)
+		}
+		if bto.Template != "" {
+			var err error
+			if t, err = tmpl.ExecuteLang(fm.Lang, bto.Template, boxItem{FrontMatter: fm, Item: t}); err != nil {
+				return nil, err
+			}
+		}
+		texts = append(texts, t)
+	}
+	return texts, nil
+}
@@ -0,0 +1,66 @@
+package tcardgen
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ladicle/tcardgen/pkg/config"
+	"github.com/Ladicle/tcardgen/pkg/hugo"
+)
+
+func TestImagePath(t *testing.T) {
+	dir := t.TempDir()
+	static := filepath.Join(dir, "static")
+	site := &hugo.SiteConfig{Dir: dir, ContentDir: "content"}
+	if err := os.MkdirAll(filepath.Join(static, "images"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(static, "images", "cover.png"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc   string
+		image  string
+		dir    string
+		site   *hugo.SiteConfig
+		expect string
+	}{
+		{desc: "Relative to the content", image: "cover.png", expect: filepath.Join("content", "posts", "cover.png")},
+		{desc: "Relative to the directory", image: "cover.png", dir: "images", expect: filepath.Join("images", "cover.png")},
+		{desc: "Site root in the static directory", image: "/images/cover.png", site: site, expect: filepath.Join(static, "images", "cover.png")},
+		{desc: "Site root in the content directory", image: "/posts/cover.png", site: site, expect: filepath.Join(dir, "content", "posts", "cover.png")},
+		{desc: "Site root without the site", image: "/images/cover.png", expect: filepath.Join(static, "images", "cover.png")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			imo := &config.ImageOption{Field: "cover", Dir: tc.dir}
+			fm := &hugo.FrontMatter{Params: map[string]interface{}{"cover": tc.image}, Site: tc.site}
+			o := newRenderOption([]RenderOption{WithContentPath(filepath.Join("content", "posts", "foo.md")), WithStaticDir(static)})
+			if got := imagePath(imo, fm, o); got != tc.expect {
+				t.Errorf("want %q, but got %q", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestRenderSkipsMissingImage(t *testing.T) {
+	cnf := &config.DrawingConfig{Elements: []config.Element{
+		{Type: config.ElementImage, Image: &config.ImageOption{Field: "cover", Width: 10, Height: 10}},
+	}}
+	r := NewRenderer(cnf, nil, image.NewRGBA(image.Rect(0, 0, 100, 100)))
+	fm := &hugo.FrontMatter{Params: map[string]interface{}{"cover": "/images/missing.png"}}
+
+	var warn bytes.Buffer
+	if _, err := r.Render(context.Background(), fm, WithStaticDir(t.TempDir()), WithWarnings(&warn)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(warn.String(), "missing.png") {
+		t.Errorf("want warning for the missing image, but got %q", warn.String())
+	}
+}
@@ -0,0 +1,111 @@
+package tcardgen_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomedium"
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/Ladicle/tcardgen/pkg/canvas"
+	"github.com/Ladicle/tcardgen/pkg/canvas/fontfamily"
+	"github.com/Ladicle/tcardgen/pkg/config"
+	"github.com/Ladicle/tcardgen/pkg/hugo"
+	"github.com/Ladicle/tcardgen/pkg/tcardgen"
+)
+
+// goFonts returns the font family of the Go fonts. Use fontfamily.LoadFromDir to load fonts from files.
+func goFonts() *fontfamily.FontFamily {
+	ffa := fontfamily.NewFontFamily("Go")
+	for style, ttf := range map[fontfamily.Style][]byte{
+		fontfamily.Regular: goregular.TTF,
+		fontfamily.Medium:  gomedium.TTF,
+		fontfamily.Bold:    gobold.TTF,
+	} {
+		f, err := truetype.Parse(ttf)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ffa.AddFont(style, f)
+	}
+	return ffa
+}
+
+// newRenderer returns the renderer with the Go fonts.
+func newRenderer() *tcardgen.Renderer {
+	tpl, err := canvas.LoadFromFile("../../example/template.png")
+	if err != nil {
+		log.Fatal(err)
+	}
+	return tcardgen.NewRenderer(&config.DrawingConfig{}, goFonts(), tpl)
+}
+
+func ExampleNewRenderer() {
+	cnf, err := config.LoadConfig("../../example/template3.config.yaml")
+	if err != nil {
+		log.Fatal(err)
+	}
+	tpl, err := canvas.LoadFromFile("../../example/template3.png")
+	if err != nil {
+		log.Fatal(err)
+	}
+	r := tcardgen.NewRenderer(cnf, goFonts(), tpl)
+	fmt.Println(r.Bounds())
+	// Output: (0,0)-(1200,628)
+}
+
+func ExampleRenderer_Render() {
+	r := newRenderer()
+	img, err := r.Render(context.Background(), &hugo.FrontMatter{
+		Title:    "Generate TwitterCard(OGP) images for your Hugo posts",
+		Author:   "Ladicle",
+		Category: "Blog",
+		Tags:     []string{"hugo", "ogp"},
+		Date:     time.Date(2020, 5, 4, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(img.Bounds())
+	// Output: (0,0)-(1200,628)
+}
+
+func ExampleRenderer_RenderTo() {
+	r := newRenderer()
+	fm, err := hugo.ParseFrontMatter(os.Stderr, "../../example/blog-post.md", time.Now())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = r.RenderTo(context.Background(), &buf, fm, canvas.JPEG,
+		tcardgen.WithContentPath("../../example/blog-post.md"), tcardgen.WithQuality(80))
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, format, err := image.Decode(&buf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(format)
+	// Output: jpeg
+}
+
+func ExampleRenderer_RenderTo_svg() {
+	r := newRenderer()
+	var buf bytes.Buffer
+	err := r.RenderTo(context.Background(), &buf, &hugo.FrontMatter{Title: "Hello, SVG"}, canvas.SVG)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(strings.Contains(buf.String(), ">Hello, SVG</text>"))
+	// Output: true
+}
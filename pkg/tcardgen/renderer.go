@@ -0,0 +1,240 @@
+// Package tcardgen renders TwitterCard(OGP) images of Hugo posts.
+//
+// The Renderer draws the elements of a drawing configuration with the front matter of a post
+// on the template image. It is safe for concurrent use, so that one renderer can be shared by
+// all cards which use the same configuration.
+package tcardgen
+
+import (
+	"context"
+	"image"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ladicle/tcardgen/pkg/canvas"
+	"github.com/Ladicle/tcardgen/pkg/canvas/fontfamily"
+	"github.com/Ladicle/tcardgen/pkg/config"
+	"github.com/Ladicle/tcardgen/pkg/hugo"
+	"github.com/Ladicle/tcardgen/pkg/tmpl"
+)
+
+// defaultStaticDir is the static directory of the Hugo site.
+const defaultStaticDir = "static"
+
+// Renderer renders the cards with the drawing configuration, fonts, and template image.
+type Renderer struct {
+	cnf *config.DrawingConfig
+	ffa *fontfamily.FontFamily
+	tpl image.Image
+
+	// images caches the decoded images which are shared by cards.
+	images sync.Map
+}
+
+// NewRenderer returns a renderer which draws the cards on the template image.
+// The unspecified options of the configuration are defaulted in place.
+func NewRenderer(cnf *config.DrawingConfig, ffa *fontfamily.FontFamily, tpl image.Image) *Renderer {
+	config.Defaulting(cnf, "")
+	return &Renderer{cnf: cnf, ffa: ffa, tpl: tpl}
+}
+
+type renderOption struct {
+	contentPath string
+	staticDir   string
+	quality     int
+	// warn is written the warnings such as the missing image
+	warn io.Writer
+}
+
+type RenderOption func(*renderOption)
+
+// WithContentPath sets the path of the post. Images in the front matter are relative to the post.
+func WithContentPath(path string) RenderOption {
+	return func(o *renderOption) {
+		o.contentPath = path
+	}
+}
+
+// WithStaticDir sets the static directory of the site. Images in the front matter which start with "/"
+// are relative to it, or the content directory of the site. The default is "static".
+func WithStaticDir(dir string) RenderOption {
+	return func(o *renderOption) {
+		o.staticDir = dir
+	}
+}
+
+// WithWarnings sets the writer of the warnings. The image element whose file does not exist is
+// skipped with the warning.
+func WithWarnings(w io.Writer) RenderOption {
+	return func(o *renderOption) {
+		o.warn = w
+	}
+}
+
+// WithQuality sets the JPEG quality from 1 to 100.
+func WithQuality(quality int) RenderOption {
+	return func(o *renderOption) {
+		o.quality = quality
+	}
+}
+
+func newRenderOption(opts []RenderOption) *renderOption {
+	o := &renderOption{staticDir: defaultStaticDir, quality: canvas.DefaultJPEGQuality, warn: io.Discard}
+	for _, f := range opts {
+		f(o)
+	}
+	return o
+}
+
+// Render renders the card of the front matter.
+func (r *Renderer) Render(ctx context.Context, fm *hugo.FrontMatter, opts ...RenderOption) (image.Image, error) {
+	c, err := r.draw(ctx, fm, canvas.PNG, newRenderOption(opts))
+	if err != nil {
+		return nil, err
+	}
+	return c.Image(), nil
+}
+
+// RenderTo renders the card of the front matter and writes it in the format.
+func (r *Renderer) RenderTo(ctx context.Context, w io.Writer, fm *hugo.FrontMatter, format canvas.Format, opts ...RenderOption) error {
+	o := newRenderOption(opts)
+	c, err := r.draw(ctx, fm, format, o)
+	if err != nil {
+		return err
+	}
+	return c.Encode(w, format, o.quality)
+}
+
+// ImageFiles returns the image files which are drawn on the card of the front matter.
+func (r *Renderer) ImageFiles(fm *hugo.FrontMatter, opts ...RenderOption) []string {
+	o := newRenderOption(opts)
+	var files []string
+	for _, e := range r.cnf.Elements {
+		if e.Type != config.ElementImage || !config.IsEnabled(e.Image.Enabled) {
+			continue
+		}
+		if path := imagePath(e.Image, fm, o); path != "" {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// CardData returns the values of the front matter which the elements draw on the card, by the dotted
+// field chains. The cards of the same data, images, and configuration are the same. The relative dates
+// are changed every day, so the data of them has the current date.
+func (r *Renderer) CardData(fm *hugo.FrontMatter) (map[string]interface{}, error) {
+	// the language localizes the dates of the templates
+	fields := []string{"Lang"}
+	for _, e := range r.cnf.Elements {
+		var text string
+		switch e.Type {
+		case config.ElementText:
+			if !config.IsEnabled(e.Text.Enabled) {
+				continue
+			}
+			text = e.Text.Template
+		case config.ElementMultiLineText:
+			if !config.IsEnabled(e.MultiLineText.Enabled) {
+				continue
+			}
+			text = e.MultiLineText.Template
+		case config.ElementBoxTexts:
+			if !config.IsEnabled(e.BoxTexts.Enabled) {
+				continue
+			}
+			text = e.BoxTexts.Template
+			fields = append(fields, sourceField(e.Source))
+		case config.ElementImage:
+			if config.IsEnabled(e.Image.Enabled) && e.Image.Field != "" {
+				fields = append(fields, "Param."+e.Image.Field)
+			}
+			continue
+		default:
+			continue
+		}
+		tf, err := tmpl.Fields(text)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, tf...)
+	}
+
+	data := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		if f == tmpl.Today {
+			data[f] = time.Now().In(fm.Date.Location()).Format(time.DateOnly)
+			continue
+		}
+		data[f] = fieldValue(fm, f)
+	}
+	return data, nil
+}
+
+// sourceField returns the field of the front matter which is the data source.
+func sourceField(src config.DataSource) string {
+	switch src {
+	case config.SourceTags:
+		return "Tags"
+	case config.SourceAuthor:
+		return "Author"
+	case config.SourceCategory:
+		return "Category"
+	}
+	return string(src)
+}
+
+// fieldValue returns the value of the dotted field chain of the front matter. The chain which starts
+// with "Param." is the parameter which falls back to the site parameter, and the other methods
+// and unknown fields such as the item of the box text read the parameters.
+func fieldValue(fm *hugo.FrontMatter, field string) interface{} {
+	if field == tmpl.WholeData {
+		return fm
+	}
+	name, rest, _ := strings.Cut(field, ".")
+	switch {
+	case name == "Item":
+		// the item of the box text is the value of the data source
+		return nil
+	case name == "Param" && rest != "":
+		return fm.Param(rest)
+	case name == "Params" && rest != "":
+		return fm.PageParam(rest)
+	}
+	v := reflect.ValueOf(fm).Elem().FieldByName(name)
+	if !v.IsValid() {
+		return fm.Params
+	}
+	return v.Interface()
+}
+
+// Bounds returns the bounds of the cards.
+func (r *Renderer) Bounds() image.Rectangle {
+	return r.tpl.Bounds()
+}
+
+// draw draws the card of the front matter on a canvas of the format.
+// SVG is drawn on the vector canvas to write the texts as text elements.
+func (r *Renderer) draw(ctx context.Context, fm *hugo.FrontMatter, format canvas.Format, o *renderOption) (*canvas.Canvas, error) {
+	create := canvas.CreateCanvasFromImage
+	if format == canvas.SVG {
+		create = canvas.CreateVectorCanvasFromImage
+	}
+	c, err := create(r.tpl)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range r.cnf.Elements {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := r.drawElement(c, e, fm, o); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,117 @@
+package tmpl
+
+import (
+	"sort"
+	"strings"
+	tparse "text/template/parse"
+)
+
+// WholeData is the field of Fields which means the template refers to the whole data.
+const WholeData = "."
+
+// Today is the field of Fields which means the template refers to the current date by "relative".
+const Today = "$today"
+
+// Fields returns the dotted field chains of the data which the text template refers, such as
+// "Title" and "Params.image". The method calls such as "Param" are also returned as the fields.
+// The fields which are referred in "with" and "range" are covered by the field of the pipeline.
+func Fields(text string) ([]string, error) {
+	t, err := Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	w := fieldWalker{fields: map[string]bool{}}
+	if t.Tree != nil {
+		w.walk(t.Tree.Root, true)
+	}
+	fields := make([]string, 0, len(w.fields))
+	for f := range w.fields {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+type fieldWalker struct {
+	fields map[string]bool
+}
+
+// walk collects the fields of the node. The dot is the data if root is true, or the value of the
+// pipeline of "with" or "range" whose fields are already collected.
+func (w *fieldWalker) walk(node tparse.Node, root bool) {
+	switch n := node.(type) {
+	case *tparse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			w.walk(c, root)
+		}
+	case *tparse.ActionNode:
+		w.walk(n.Pipe, root)
+	case *tparse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			if id, ok := cmd.Args[0].(*tparse.IdentifierNode); ok && id.Ident == "relative" {
+				w.fields[Today] = true
+			}
+			for _, arg := range cmd.Args {
+				w.walk(arg, root)
+			}
+		}
+	case *tparse.IfNode:
+		w.walk(n.Pipe, root)
+		w.walk(n.List, root)
+		w.walk(n.ElseList, root)
+	case *tparse.WithNode:
+		w.walk(n.Pipe, root)
+		w.walk(n.List, false)
+		w.walk(n.ElseList, root)
+	case *tparse.RangeNode:
+		w.walk(n.Pipe, root)
+		w.walk(n.List, false)
+		w.walk(n.ElseList, root)
+	case *tparse.TemplateNode:
+		w.walk(n.Pipe, root)
+	case *tparse.FieldNode:
+		if root {
+			w.add(n.Ident)
+		}
+	case *tparse.DotNode:
+		if root {
+			w.add(nil)
+		}
+	case *tparse.VariableNode:
+		// the other variables are the values of the pipelines whose fields are already collected
+		if n.Ident[0] == "$" {
+			w.add(n.Ident[1:])
+		}
+	case *tparse.ChainNode:
+		switch c := n.Node.(type) {
+		case *tparse.DotNode:
+			if root {
+				w.add(n.Field)
+			}
+		case *tparse.FieldNode:
+			if root {
+				w.add(append(append([]string{}, c.Ident...), n.Field...))
+			}
+		case *tparse.VariableNode:
+			if c.Ident[0] == "$" {
+				w.add(append(append([]string{}, c.Ident[1:]...), n.Field...))
+			}
+		default:
+			w.walk(n.Node, root)
+		}
+	}
+}
+
+func (w *fieldWalker) add(idents []string) {
+	if len(idents) == 0 {
+		w.fields[WholeData] = true
+		return
+	}
+	w.fields[strings.Join(idents, ".")] = true
+}
@@ -0,0 +1,141 @@
+package tmpl
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/Ladicle/tcardgen/pkg/locale"
+)
+
+// FuncMap is a set of helper functions which are available in the text templates.
+var FuncMap = template.FuncMap{
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"title":    strings.Title,
+	"trim":     strings.TrimSpace,
+	"truncate": truncate,
+	"join":     join,
+	"date":     date,
+	"cldate":   cldate(""),
+	"updated":  updated(""),
+	"relative": relative(""),
+	"default":  defaultValue,
+}
+
+var cache sync.Map
+
+// Parse parses the text template with FuncMap.
+// Parsed templates are cached and shared, so the same text is parsed only once.
+func Parse(text string) (*template.Template, error) {
+	return parse("", text)
+}
+
+// parse parses the text template whose date functions format the time in the language.
+func parse(lang, text string) (*template.Template, error) {
+	key := lang + "\x00" + text
+	if t, ok := cache.Load(key); ok {
+		return t.(*template.Template), nil
+	}
+	t := template.New("text").Funcs(FuncMap)
+	if lang != "" {
+		t.Funcs(template.FuncMap{
+			"date": func(layout string, t time.Time) string {
+				return locale.FormatTime(lang, layout, t)
+			},
+			"cldate":   cldate(lang),
+			"updated":  updated(lang),
+			"relative": relative(lang),
+		})
+	}
+	t, err := t.Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %q: %w", text, err)
+	}
+	cache.Store(key, t)
+	return t, nil
+}
+
+// Execute applies the text template to the data and returns the result.
+func Execute(text string, data interface{}) (string, error) {
+	return ExecuteLang("", text, data)
+}
+
+// ExecuteLang applies the text template to the data like Execute, and the "date", "cldate",
+// "updated", and "relative" functions localize the dates in the language.
+func ExecuteLang(lang, text string, data interface{}) (string, error) {
+	t, err := parse(lang, text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// truncate cuts the text to the length of n characters and adds "…" to the end if it is cut.
+func truncate(n int, s string) string {
+	rs := []rune(s)
+	if n < 0 || len(rs) <= n {
+		return s
+	}
+	return string(rs[:n]) + "…"
+}
+
+// join concatenates the items with the separator.
+func join(sep string, items []string) string {
+	return strings.Join(items, sep)
+}
+
+// date formats the time with the Go layout string.
+func date(layout string, t time.Time) string {
+	return t.Format(layout)
+}
+
+// cldate returns the function which formats the time with the CLDR date pattern or style in the
+// language. The calendar is optional and precedes the time, such as `cldate "long" "japanese"`.
+func cldate(lang string) func(string, ...interface{}) (string, error) {
+	return func(pattern string, args ...interface{}) (string, error) {
+		if len(args) == 0 || len(args) > 2 {
+			return "", fmt.Errorf("cldate: want the calendar and time, but got %d arguments", len(args))
+		}
+		t, ok := args[len(args)-1].(time.Time)
+		if !ok {
+			return "", fmt.Errorf("cldate: want time.Time, but got %T", args[len(args)-1])
+		}
+		var calendar string
+		if len(args) == 2 {
+			if calendar, ok = args[0].(string); !ok {
+				return "", fmt.Errorf("cldate: want the calendar name, but got %T", args[0])
+			}
+		}
+		return locale.FormatDate(lang, calendar, pattern, t)
+	}
+}
+
+// updated returns the function which shows the date is updated in the language.
+func updated(lang string) func(string) string {
+	return func(date string) string {
+		return locale.Updated(lang, date)
+	}
+}
+
+// relative returns the function which shows the date relative to the current time in the language.
+func relative(lang string) func(time.Time) string {
+	return func(t time.Time) string {
+		return locale.Relative(lang, t, time.Now())
+	}
+}
+
+// defaultValue returns the default value if the given value is empty.
+func defaultValue(def, v string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
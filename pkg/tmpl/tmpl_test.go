@@ -0,0 +1,115 @@
+package tmpl
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExecute(t *testing.T) {
+	data := struct {
+		Title  string
+		Author string
+		Tags   []string
+		Date   time.Time
+	}{
+		Title:  "Generate a TwitterCard image for your Hugo posts",
+		Author: "@Ladicle",
+		Tags:   []string{"hugo", "go"},
+		Date:   time.Date(2020, 6, 23, 8, 29, 14, 0, time.UTC),
+	}
+
+	testCases := []struct {
+		desc   string
+		text   string
+		expect string
+	}{
+		{desc: "Plain field", text: "{{ .Title }}", expect: "Generate a TwitterCard image for your Hugo posts"},
+		{desc: "Upper", text: "{{ .Author | upper }}", expect: "@LADICLE"},
+		{desc: "Truncate", text: "{{ .Title | truncate 8 }}", expect: "Generate…"},
+		{desc: "Truncate short text", text: "{{ .Author | truncate 8 }}", expect: "@Ladicle"},
+		{desc: "Join", text: `{{ .Tags | join ", " }}`, expect: "hugo, go"},
+		{desc: "Date", text: `{{ .Author }} · {{ .Date | date "2006-01-02" }}`, expect: "@Ladicle · 2020-06-23"},
+		{desc: "CLDR date", text: `{{ .Date | cldate "EEE, MMM d" }}`, expect: "Tue, Jun 23"},
+		{desc: "CLDR date in calendar", text: `{{ .Date | cldate "long" "japanese" }}`, expect: "June 23, 2 Reiwa"},
+		{desc: "Updated", text: `{{ .Date | date "Jan 2" | updated }}`, expect: "Updated Jun 23"},
+		{desc: "Default", text: `{{ .Author | default "anonymous" }}{{ "" | default "-" }}`, expect: "@Ladicle-"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := Execute(tc.text, data)
+			if err != nil {
+				t.Fatalf("failed to execute template: %v", err)
+			}
+			if got != tc.expect {
+				t.Fatalf("Execute() returns unexpected value: got=%q, want=%q", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestExecuteLang(t *testing.T) {
+	data := struct{ Date time.Time }{Date: time.Date(2020, 6, 23, 8, 29, 14, 0, time.UTC)}
+	text := `{{ .Date | date "Jan 2" }}`
+
+	for lang, expect := range map[string]string{"": "Jun 23", "en": "Jun 23", "de": "Juni 23", "ja": "6月 23"} {
+		got, err := ExecuteLang(lang, text, data)
+		if err != nil {
+			t.Fatalf("failed to execute template: %v", err)
+		}
+		if got != expect {
+			t.Errorf("ExecuteLang(%q) returns unexpected value: got=%q, want=%q", lang, got, expect)
+		}
+	}
+
+	text = `{{ .Date | cldate "long" | updated }}`
+	for lang, expect := range map[string]string{"": "Updated June 23, 2020", "ja": "2020年6月23日 更新", "fr": "Mis à jour le 23 juin 2020"} {
+		got, err := ExecuteLang(lang, text, data)
+		if err != nil {
+			t.Fatalf("failed to execute template: %v", err)
+		}
+		if got != expect {
+			t.Errorf("ExecuteLang(%q) returns unexpected value: got=%q, want=%q", lang, got, expect)
+		}
+	}
+
+	data.Date = time.Now().AddDate(0, 0, -3)
+	for lang, expect := range map[string]string{"": "3 days ago", "ja": "3日前", "de": "vor 3 Tagen"} {
+		got, err := ExecuteLang(lang, `{{ .Date | relative }}`, data)
+		if err != nil {
+			t.Fatalf("failed to execute template: %v", err)
+		}
+		if got != expect {
+			t.Errorf("ExecuteLang(%q) returns unexpected value: got=%q, want=%q", lang, got, expect)
+		}
+	}
+
+	if _, err := Execute(`{{ .Date | cldate "long" "lunar" }}`, data); err == nil {
+		t.Error("want error for unsupported calendar")
+	}
+}
+
+func TestFields(t *testing.T) {
+	testCases := []struct {
+		text   string
+		expect []string
+	}{
+		{text: "{{ .Title }}", expect: []string{"Title"}},
+		{text: `{{ .Date | date "Jan 2" }} · {{ .Params.series.name | upper }}`, expect: []string{"Date", "Params.series.name"}},
+		{text: `{{ with .Params.lead }}{{ .text }}{{ else }}{{ .Description }}{{ end }}`, expect: []string{"Description", "Params.lead"}},
+		{text: `{{ range $i, $t := .Tags }}{{ $t }}{{ $.Author }}{{ end }}`, expect: []string{"Author", "Tags"}},
+		{text: `{{ .Param "subtitle" }}{{ (.Site).Author }}`, expect: []string{"Param", "Site"}},
+		{text: `{{ printf "%v" . }}`, expect: []string{WholeData}},
+		{text: `{{ .Date | relative }}`, expect: []string{Today, "Date"}},
+		{text: "plain text", expect: []string{}},
+	}
+	for _, tc := range testCases {
+		got, err := Fields(tc.text)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.text, err)
+		}
+		if !reflect.DeepEqual(got, tc.expect) {
+			t.Errorf("%q: want %v, but got %v", tc.text, tc.expect, got)
+		}
+	}
+}